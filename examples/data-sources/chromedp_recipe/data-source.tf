@@ -11,3 +11,62 @@ data "chromedp_recipe" "example" {
     ["text", "#example-After div.Documentation-exampleDetailsBody pre span.Documentation-exampleOutput", "runtext"],
   ]
 }
+
+data "chromedp_recipe" "typed" {
+  timeout = "5m"
+  retry = {
+    attempts = 2
+    on       = ["timeout", "network"]
+  }
+  steps = [
+    { navigate = { url = "https://pkg.go.dev/time" }, retry = { attempts = 3, backoff = "2s" } },
+    { wait_visible = { selector = "body footer" }, timeout = "30s" },
+    { click = { selector = "#example-After", wait_visible = true } },
+    { text = { selector = "div.Documentation-function:has(#After) p", name = "description" } },
+    { sleep = { duration = "3s" } },
+  ]
+}
+
+data "chromedp_recipe" "full_page" {
+  screenshot_filename  = "full_page.jpeg"
+  screenshot_full_page = true
+  screenshot_format    = "jpeg"
+  screenshot_quality   = 80
+  actions = [
+    ["navigate", "https://pkg.go.dev/time"],
+    ["wait_visible", "body footer"],
+  ]
+}
+
+data "chromedp_recipe" "visual" {
+  screenshot = true
+  actions = [
+    ["navigate", "https://pkg.go.dev/time"],
+    ["wait_visible", "body footer"],
+  ]
+}
+
+output "page_screenshot_sha256" {
+  value = data.chromedp_recipe.visual.screenshot_sha256
+}
+
+data "chromedp_recipe" "latest_release" {
+  actions = [
+    ["navigate", "https://go.dev/dl/"],
+    ["attribute", "a.download", "href", "download_path"],
+    ["navigate", "https://go.dev$${values.download_path}"],
+    ["evaluate", "location.href", "download_url"],
+  ]
+}
+
+data "chromedp_recipe" "releases" {
+  actions = [
+    ["navigate", "https://github.com/chromedp/chromedp/releases"],
+    ["if_visible", "#cookie-consent button.accept"],
+    ["click", "#cookie-consent button.accept"],
+    ["end"],
+    ["for_each_node", "section h2"],
+    ["text", "$${node}", "release_$${node.index}"],
+    ["end"],
+  ]
+}
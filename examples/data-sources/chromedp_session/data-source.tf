@@ -0,0 +1,23 @@
+variable "password" {
+  type      = string
+  sensitive = true
+}
+
+data "chromedp_session" "login" {
+  actions = [
+    ["navigate", "https://example.com/login"],
+    ["wait_visible", "#password"],
+    ["set_value", "#username", "terraform"],
+    ["set_value", "#password", var.password],
+    ["click", "button[type=submit]"],
+    ["wait_visible", "#dashboard"],
+  ]
+}
+
+data "chromedp_recipe" "dashboard" {
+  session = data.chromedp_session.login.session
+  actions = [
+    ["navigate", "https://example.com/dashboard"],
+    ["text", "#dashboard .balance", "balance"],
+  ]
+}
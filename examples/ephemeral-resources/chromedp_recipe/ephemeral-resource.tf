@@ -0,0 +1,24 @@
+data "chromedp_session" "grafana" {
+  actions = [
+    ["navigate", "https://grafana.example.com/login"],
+    ["set_value", "input[name=user]", "admin"],
+    ["set_value", "input[name=password]", var.grafana_password],
+    ["click", "button[type=submit]"],
+    ["wait_visible", "nav"],
+  ]
+}
+
+ephemeral "chromedp_recipe" "grafana_token" {
+  session = data.chromedp_session.grafana.session
+  actions = [
+    ["navigate", "https://grafana.example.com/org/serviceaccounts/1/tokens/new"],
+    ["click", "button[type=submit]"],
+    ["wait_visible", "input#token"],
+    ["value", "input#token", "token"],
+  ]
+}
+
+provider "grafana" {
+  url  = "https://grafana.example.com"
+  auth = ephemeral.chromedp_recipe.grafana_token.values.token
+}
@@ -7,5 +7,21 @@ terraform {
 }
 
 provider "chromedp" {
-  endpoint = "ws://localhost:3000"
+  endpoint               = "ws://localhost:3000"
+  default_action_timeout = "1m"
+}
+
+# Local Chrome running in a container behind a corporate proxy
+provider "chromedp" {
+  alias        = "local"
+  headless     = true
+  no_sandbox   = true
+  proxy_server = "http://proxy.example.com:3128"
+  window_size = {
+    width  = 1920
+    height = 1080
+  }
+  extra_flags = {
+    "ignore-certificate-errors" = "true"
+  }
 }
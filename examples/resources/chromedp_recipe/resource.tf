@@ -0,0 +1,34 @@
+data "chromedp_session" "admin" {
+  actions = [
+    ["navigate", "https://wiki.example.com/login"],
+    ["set_value", "#username", "admin"],
+    ["set_value", "#password", var.admin_password],
+    ["click", "#login"],
+    ["wait_visible", "#dashboard"],
+  ]
+}
+
+resource "chromedp_recipe" "wiki_theme" {
+  session = data.chromedp_session.admin.session
+
+  create_actions = [
+    ["navigate", "https://wiki.example.com/admin/appearance"],
+    ["set_value", "select#theme", "dark"],
+    ["click", "#save"],
+    ["wait_visible", ".notice-success"],
+  ]
+  read_actions = [
+    ["navigate", "https://wiki.example.com/admin/appearance"],
+    ["value", "select#theme", "theme"],
+  ]
+  delete_actions = [
+    ["navigate", "https://wiki.example.com/admin/appearance"],
+    ["set_value", "select#theme", "default"],
+    ["click", "#save"],
+    ["wait_visible", ".notice-success"],
+  ]
+
+  expected_values = {
+    theme = "dark"
+  }
+}
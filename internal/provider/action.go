@@ -2,23 +2,41 @@ package provider
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/dom"
 	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/cdproto/runtime"
 	"github.com/chromedp/chromedp"
 	"github.com/chromedp/chromedp/kb"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 )
 
 type Action struct {
 	dpaction  chromedp.Action
 	valueName string
-	value     *string
+	// value is pointer to the result of the action, see outputValues for supported types.
+	value interface{}
+	// timeout overrides the default timeout of the action, 0 means the default.
+	timeout time.Duration
+	// retry overrides fields of the retry policy of the recipe.
+	retry retryPolicy
+	// sensitive places the result into "sensitive_values" instead of "values".
+	sensitive bool
+	// block is set by actions opening blocks of nested actions, blockEnd by "end" action closing them.
+	block    *actionBlock
+	blockEnd bool
 }
 
-func NewAction(action chromedp.Action, valueName string, value *string) *Action {
+func NewAction(action chromedp.Action, valueName string, value interface{}) *Action {
 	return &Action{
 		dpaction:  action,
 		valueName: valueName,
@@ -26,38 +44,267 @@ func NewAction(action chromedp.Action, valueName string, value *string) *Action
 	}
 }
 
-func (a *Action) Action(values map[string]*string) chromedp.Action {
+func (a *Action) Action(values map[string]interface{}) chromedp.Action {
 	if a.valueName != "" && a.value != nil {
-		values[a.valueName] = a.value
+		if a.sensitive {
+			values[a.valueName] = sensitiveOutput{value: a.value}
+		} else {
+			values[a.valueName] = a.value
+		}
 	}
 	return a.dpaction
 }
 
+// actionArgError is returned by actionBuilder when specific argument of the action is wrong.
+type actionArgError struct {
+	// index of the argument in the action including the verb.
+	index int
+	err   error
+}
+
+func argError(index int, err error) error {
+	return &actionArgError{index: index, err: err}
+}
+
+func (e *actionArgError) Error() string {
+	return e.err.Error()
+}
+
+func (e *actionArgError) Unwrap() error {
+	return e.err
+}
+
+// actionDefinition is arguments of the action together with its path in the configuration.
+type actionDefinition struct {
+	args []types.String
+	path path.Path
+	// positional is true if every argument has its own path under the action path (list of strings form).
+	positional bool
+	// columns are renames of columns of the table action defined by typed steps.
+	columns map[string]string
+}
+
+// build builds the action of the definition from its arguments, they may differ from args by placed values.
+func (a actionDefinition) build(args []types.String) (*Action, error) {
+	return buildAction(args, a.columns)
+}
+
+// errorPath returns the most specific path for the error returned by actionBuilder.
+func (a actionDefinition) errorPath(err error) path.Path {
+	var argErr *actionArgError
+	if a.positional && errors.As(err, &argErr) {
+		return a.path.AtListIndex(argErr.index)
+	}
+	return a.path
+}
+
+// validateActions runs actionBuilder over list of string lists actions during config validation.
+// Actions with unknown verb or unknown list of arguments are skipped, blocks are checked only if no action is skipped.
+func validateActions(actions types.List, actionsPath path.Path) diag.Diagnostics {
+	if actions.IsNull() || actions.IsUnknown() {
+		return nil
+	}
+
+	defs := make([]*actionDefinition, 0, len(actions.Elements()))
+	for i, elem := range actions.Elements() {
+		action, ok := elem.(types.List)
+		if !ok || action.IsNull() || action.IsUnknown() {
+			defs = append(defs, nil)
+			continue
+		}
+
+		def := &actionDefinition{path: actionsPath.AtListIndex(i), positional: true}
+		for _, argElem := range action.Elements() {
+			arg, ok := argElem.(types.String)
+			if !ok {
+				continue
+			}
+			def.args = append(def.args, arg)
+		}
+		if len(def.args) > 0 && def.args[0].IsUnknown() {
+			defs = append(defs, nil)
+			continue
+		}
+		defs = append(defs, def)
+	}
+	return validateDefinitions(defs)
+}
+
+// validateSteps runs actionBuilder over typed steps during config validation like validateActions.
+// Steps with unknown objects are skipped, steps not defining exactly one action are reported by stepValidator.
+func validateSteps(ctx context.Context, steps types.List, stepsPath path.Path) diag.Diagnostics {
+	if steps.IsNull() || steps.IsUnknown() {
+		return nil
+	}
+
+	defs := make([]*actionDefinition, 0, len(steps.Elements()))
+	for i, elem := range steps.Elements() {
+		object, ok := elem.(types.Object)
+		if !ok || object.IsNull() || object.IsUnknown() {
+			defs = append(defs, nil)
+			continue
+		}
+		var step StepModel
+		if diags := object.As(ctx, &step, basetypes.ObjectAsOptions{}); diags.HasError() {
+			defs = append(defs, nil)
+			continue
+		}
+		args, err := step.actionArgs()
+		if err != nil {
+			defs = append(defs, nil)
+			continue
+		}
+		defs = append(defs, &actionDefinition{args: args, path: stepsPath.AtListIndex(i), columns: step.tableColumns()})
+	}
+	return validateDefinitions(defs)
+}
+
+// validateDefinitions checks definitions of actions during config validation, nil definitions are actions which are not known yet.
+func validateDefinitions(defs []*actionDefinition) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	// opened are definitions of block actions not closed yet, they are unreliable once an action is skipped.
+	var opened []actionDefinition
+	skipped := false
+	for _, def := range defs {
+		if def == nil {
+			skipped = true
+			continue
+		}
+
+		built, err := def.build(withUnknownPlaceholders(def.args))
+		if err == nil && !skipped {
+			if i, nodeErr := checkNodePlaceholders(def.args, inNodeLoop(opened)); nodeErr != nil {
+				err = argError(i, nodeErr)
+			}
+		}
+		if err != nil {
+			diags.AddAttributeError(def.errorPath(err), "Wrong action definition", err.Error())
+			continue
+		}
+		switch {
+		case skipped:
+		case built.block != nil:
+			opened = append(opened, *def)
+		case built.blockEnd && len(opened) == 0:
+			diags.AddAttributeError(def.path, "Wrong action definition", errEndWithoutBlock.Error())
+		case built.blockEnd:
+			opened = opened[:len(opened)-1]
+		}
+	}
+	if !skipped {
+		for _, def := range opened {
+			diags.AddAttributeError(def.path, "Wrong action definition", unclosedBlockError(def.args[0].ValueString()).Error())
+		}
+	}
+	return diags
+}
+
+// parseDurationArg parses the duration argument. Unknown values are only possible during config validation and are accepted as is.
+func parseDurationArg(arg types.String) (time.Duration, error) {
+	if arg.IsUnknown() {
+		return 0, nil
+	}
+	return time.ParseDuration(arg.ValueString())
+}
+
+// parseWebStorageArg parses the storage argument, unknown values are accepted like by parseDurationArg.
+func parseWebStorageArg(arg types.String) (webStorage, error) {
+	if arg.IsUnknown() {
+		return localWebStorage, nil
+	}
+	return parseWebStorage(arg.ValueString())
+}
+
+// commonOptions are trailing options accepted by every action.
+type commonOptions struct {
+	// timeout overrides the default timeout of the action, 0 means the default.
+	timeout time.Duration
+	// retry overrides fields of the retry policy of the recipe.
+	retry retryPolicy
+	// sensitive hides the result and the arguments of the action.
+	sensitive bool
+}
+
+// cutCommonArgs removes trailing options accepted by every action: "timeout=<duration>", retry options and "sensitive".
+// Options are recognized only after the required number of arguments, so ["send_keys", "#q", "timeout=5"] types "timeout=5".
+func cutCommonArgs(args []types.String, required int) (commonOptions, []types.String, error) {
+	var opts commonOptions
+	for len(args) > required {
+		// The verb is the first argument of the action, so the last argument has index len(args).
+		last := args[len(args)-1].ValueString()
+		if last == "sensitive" {
+			opts.sensitive = true
+		} else if strings.HasPrefix(last, "timeout=") {
+			value := strings.TrimPrefix(last, "timeout=")
+			var err error
+			opts.timeout, err = time.ParseDuration(value)
+			if err != nil || opts.timeout <= 0 {
+				return commonOptions{}, nil, argError(len(args), fmt.Errorf("timeout option must be positive duration like \"30s\", got %q", value))
+			}
+		} else {
+			ok, err := opts.retry.parse(last)
+			if err != nil {
+				return commonOptions{}, nil, argError(len(args), err)
+			}
+			if !ok {
+				break
+			}
+		}
+		args = args[:len(args)-1]
+	}
+	return opts, args, nil
+}
+
+// actionBuilder builds the action from list of arguments where the first one is the verb.
+// Unknown arguments pass the parsing, so the same function is used for config validation.
 func actionBuilder(actionArgs []types.String) (*Action, error) {
+	return buildAction(actionArgs, nil)
+}
+
+// buildAction is actionBuilder which also renames columns of the table action, on top of its column options.
+func buildAction(actionArgs []types.String, columns map[string]string) (*Action, error) {
 	if len(actionArgs) < 1 {
 		return nil, fmt.Errorf("malformed action")
 	}
 	verb := actionArgs[0]
 	args := actionArgs[1:]
 
+	// Every verb cuts common options following its required arguments before it parses them.
+	var common commonOptions
+	cutOptions := func(required int) error {
+		var err error
+		common, args, err = cutCommonArgs(args, required)
+		return err
+	}
+
 	var dpAction chromedp.Action
 	var valueName string
-	var outputValue *string
+	var outputValue interface{}
 
 	switch verb.ValueString() {
 	case "navigate":
+		if err := cutOptions(1); err != nil {
+			return nil, err
+		}
 		if len(args) != 1 {
 			return nil, fmt.Errorf("navigate action expects only 1 argument (URL), got %d: %v", len(args), args)
 		}
 		url := args[0].ValueString()
 		dpAction = chromedp.Navigate(url)
 	case "wait_visible":
+		if err := cutOptions(1); err != nil {
+			return nil, err
+		}
 		if len(args) != 1 {
 			return nil, fmt.Errorf("wait_visible action expects only 1 argument (selector), got %d: %v", len(args), args)
 		}
 		selector := args[0].ValueString()
 		dpAction = chromedp.WaitVisible(selector)
 	case "click":
+		if err := cutOptions(1); err != nil {
+			return nil, err
+		}
 		if len(args) < 1 {
 			return nil, fmt.Errorf("click action expects at 1 least argument (selector and options), got %d: %v", len(args), args)
 		}
@@ -71,61 +318,126 @@ func actionBuilder(actionArgs []types.String) (*Action, error) {
 		}
 		dpAction = chromedp.Click(selector, opts...)
 	case "value":
+		if err := cutOptions(2); err != nil {
+			return nil, err
+		}
 		if len(args) != 2 {
 			return nil, fmt.Errorf("value action expects 2 arguments (selector and value name), got %d: %v", len(args), args)
 		}
 		selector := args[0].ValueString()
 		valueName = args[1].ValueString()
-		outputValue = new(string)
-		dpAction = chromedp.Value(selector, outputValue)
+		value := new(string)
+		outputValue = value
+		dpAction = chromedp.Value(selector, value)
 	case "focus":
+		if err := cutOptions(1); err != nil {
+			return nil, err
+		}
 		if len(args) != 1 {
 			return nil, fmt.Errorf("focus action expects only 1 argument (selector), got %d: %v", len(args), args)
 		}
 		selector := args[0].ValueString()
 		dpAction = chromedp.Focus(selector)
 	case "sleep":
+		if err := cutOptions(1); err != nil {
+			return nil, err
+		}
 		if len(args) != 1 {
 			return nil, fmt.Errorf("sleep action expects only 1 argument (duration), got %d: %v", len(args), args)
 		}
-		d, err := time.ParseDuration(args[0].ValueString())
+		d, err := parseDurationArg(args[0])
 		if err != nil {
-			return nil, fmt.Errorf("can't parse duration for sleep: %w", err)
+			return nil, argError(1, fmt.Errorf("can't parse duration for sleep: %w", err))
 		}
 		dpAction = chromedp.Sleep(d)
 	case "text":
+		if err := cutOptions(2); err != nil {
+			return nil, err
+		}
 		if len(args) != 2 {
 			return nil, fmt.Errorf("text action expects 2 arguments (selector and value name), got %d: %v", len(args), args)
 		}
 		selector := args[0].ValueString()
 		valueName = args[1].ValueString()
-		outputValue = new(string)
-		dpAction = chromedp.TextContent(selector, outputValue)
+		text := new(string)
+		outputValue = text
+		dpAction = chromedp.TextContent(selector, text)
 	case "cookie":
+		if err := cutOptions(2); err != nil {
+			return nil, err
+		}
 		if len(args) < 2 {
-			return nil, fmt.Errorf("cookie action expects at least 2 arguments (cookie name, value, and optional domain), got %d: %v", len(args), args)
+			return nil, fmt.Errorf("cookie action expects at least 2 arguments (cookie name, value, optional domain and options), got %d: %v", len(args), args)
 		}
 		cookieName := args[0].ValueString()
 		cookieValue := args[1].ValueString()
-		cookieDomain := ""
-
-		if len(args) == 2 {
-			cookieDomain = args[2].ValueString()
+		var opts cookieOptions
+		for i := 2; i < len(args); i++ {
+			if args[i].IsUnknown() {
+				continue
+			}
+			ok, err := opts.parse(args[i].ValueString())
+			if err != nil {
+				return nil, argError(i+1, err)
+			}
+			if !ok {
+				// The third argument is the domain in the short form of the action.
+				if i != 2 {
+					return nil, argError(i+1, fmt.Errorf("unknown cookie option %s", args[i]))
+				}
+				opts.domain = args[i].ValueString()
+			}
 		}
-		dpAction = chromedp.ActionFunc(func(ctx context.Context) error {
-			expr := cdp.TimeSinceEpoch(time.Now().Add(24 * time.Hour))
-			setcookieBuilder := network.SetCookie(cookieName, cookieValue).
-				WithExpires(&expr)
-			if cookieDomain != "" {
-				setcookieBuilder = setcookieBuilder.WithDomain(cookieDomain)
+		if err := opts.validate(); err != nil {
+			return nil, err
+		}
+		dpAction = setCookie(cookieName, cookieValue, opts)
+	case "get_cookies":
+		if err := cutOptions(1); err != nil {
+			return nil, err
+		}
+		if len(args) < 1 {
+			return nil, fmt.Errorf("get_cookies action expects at least 1 argument (value name and optional URLs), got %d: %v", len(args), args)
+		}
+		valueName = args[0].ValueString()
+		var urls []string
+		for _, url := range args[1:] {
+			urls = append(urls, url.ValueString())
+		}
+		cookies := &[]*network.Cookie{}
+		outputValue = cookies
+		dpAction = getCookies(urls, cookies)
+	case "delete_cookie":
+		if err := cutOptions(1); err != nil {
+			return nil, err
+		}
+		if len(args) < 1 {
+			return nil, fmt.Errorf("delete_cookie action expects at least 1 argument (cookie name and options), got %d: %v", len(args), args)
+		}
+		cookieName := args[0].ValueString()
+		var opts cookieOptions
+		for i := 1; i < len(args); i++ {
+			if args[i].IsUnknown() {
+				continue
 			}
-			err := setcookieBuilder.Do(ctx)
+			err := opts.parseScope(args[i].ValueString())
 			if err != nil {
-				return err
+				return nil, argError(i+1, err)
 			}
-			return nil
-		})
+		}
+		dpAction = deleteCookie(cookieName, opts)
+	case "clear_cookies":
+		if err := cutOptions(0); err != nil {
+			return nil, err
+		}
+		if len(args) != 0 {
+			return nil, fmt.Errorf("clear_cookies action expects 0 arguments, got %d: %v", len(args), args)
+		}
+		dpAction = network.ClearBrowserCookies()
 	case "set_value":
+		if err := cutOptions(2); err != nil {
+			return nil, err
+		}
 		if len(args) != 2 {
 			return nil, fmt.Errorf("set_value action expects 2 arguments (selector and value), got %d: %v", len(args), args)
 		}
@@ -133,20 +445,380 @@ func actionBuilder(actionArgs []types.String) (*Action, error) {
 		value := args[1].ValueString()
 		dpAction = chromedp.SetValue(selector, value)
 	case "press_enter":
-		if len(args) != 0 {
-			return nil, fmt.Errorf("press_enter action expects 0 arguments, got %d: %v", len(args), args)
+		if err := cutOptions(1); err != nil {
+			return nil, err
+		}
+		if len(args) != 1 {
+			return nil, fmt.Errorf("press_enter action expects only 1 argument (selector), got %d: %v", len(args), args)
 		}
 		selector := args[0].ValueString()
 		dpAction = chromedp.SendKeys(selector, kb.Enter)
 	case "send_keys":
+		if err := cutOptions(2); err != nil {
+			return nil, err
+		}
 		if len(args) != 2 {
 			return nil, fmt.Errorf("send_keys action expects 2 arguments (selector and value), got %d: %v", len(args), args)
 		}
 		selector := args[0].ValueString()
 		value := args[1].ValueString()
 		dpAction = chromedp.SendKeys(selector, value)
+	case "text_all":
+		if err := cutOptions(2); err != nil {
+			return nil, err
+		}
+		if len(args) != 2 {
+			return nil, fmt.Errorf("text_all action expects 2 arguments (selector and value name), got %d: %v", len(args), args)
+		}
+		selector := args[0].ValueString()
+		valueName = args[1].ValueString()
+		texts := &[]string{}
+		outputValue = texts
+		dpAction = queryAll(selector, texts, nodeFunction(textContentJS))
+	case "value_all":
+		if err := cutOptions(2); err != nil {
+			return nil, err
+		}
+		if len(args) != 2 {
+			return nil, fmt.Errorf("value_all action expects 2 arguments (selector and value name), got %d: %v", len(args), args)
+		}
+		selector := args[0].ValueString()
+		valueName = args[1].ValueString()
+		nodeValues := &[]string{}
+		outputValue = nodeValues
+		dpAction = queryAll(selector, nodeValues, nodeFunction(valueJS))
+	case "attribute_all":
+		if err := cutOptions(3); err != nil {
+			return nil, err
+		}
+		if len(args) != 3 {
+			return nil, fmt.Errorf("attribute_all action expects 3 arguments (selector, attribute name and value name), got %d: %v", len(args), args)
+		}
+		selector := args[0].ValueString()
+		attributeName := args[1].ValueString()
+		valueName = args[2].ValueString()
+		attributes := &[]string{}
+		outputValue = attributes
+		dpAction = queryAll(selector, attributes, func(ctx context.Context, node *cdp.Node) (string, error) {
+			return node.AttributeValue(attributeName), nil
+		})
+	case "attribute":
+		if err := cutOptions(3); err != nil {
+			return nil, err
+		}
+		if len(args) != 3 {
+			return nil, fmt.Errorf("attribute action expects 3 arguments (selector, attribute name and value name), got %d: %v", len(args), args)
+		}
+		selector := args[0].ValueString()
+		attributeName := args[1].ValueString()
+		valueName = args[2].ValueString()
+		attribute := new(string)
+		outputValue = attribute
+		dpAction = chromedp.AttributeValue(selector, attributeName, attribute, nil)
+	case "attributes":
+		if err := cutOptions(2); err != nil {
+			return nil, err
+		}
+		if len(args) != 2 {
+			return nil, fmt.Errorf("attributes action expects 2 arguments (selector and value name), got %d: %v", len(args), args)
+		}
+		selector := args[0].ValueString()
+		valueName = args[1].ValueString()
+		attributes := &map[string]string{}
+		outputValue = attributes
+		dpAction = chromedp.Attributes(selector, attributes)
+	case "set_attribute":
+		if err := cutOptions(3); err != nil {
+			return nil, err
+		}
+		if len(args) != 3 {
+			return nil, fmt.Errorf("set_attribute action expects 3 arguments (selector, attribute name and value), got %d: %v", len(args), args)
+		}
+		selector := args[0].ValueString()
+		attributeName := args[1].ValueString()
+		value := args[2].ValueString()
+		dpAction = chromedp.SetAttributeValue(selector, attributeName, value)
+	case "remove_attribute":
+		if err := cutOptions(2); err != nil {
+			return nil, err
+		}
+		if len(args) != 2 {
+			return nil, fmt.Errorf("remove_attribute action expects 2 arguments (selector and attribute name), got %d: %v", len(args), args)
+		}
+		selector := args[0].ValueString()
+		attributeName := args[1].ValueString()
+		dpAction = chromedp.RemoveAttribute(selector, attributeName)
+	case "table":
+		if err := cutOptions(2); err != nil {
+			return nil, err
+		}
+		if len(args) < 2 {
+			return nil, fmt.Errorf("table action expects at least 2 arguments (selector, value name and options), got %d: %v", len(args), args)
+		}
+		selector := args[0].ValueString()
+		valueName = args[1].ValueString()
+		opts := tableOptions{columns: make(map[string]string, len(columns))}
+		for header, name := range columns {
+			opts.columns[header] = name
+		}
+		for i := 2; i < len(args); i++ {
+			if args[i].IsUnknown() {
+				continue
+			}
+			err := opts.parse(args[i].ValueString())
+			if err != nil {
+				return nil, argError(i+1, err)
+			}
+		}
+		rows := &[]map[string]string{}
+		outputValue = rows
+		dpAction = table(selector, rows, opts)
+	case "evaluate":
+		// The value name is optional, but it's never taken for options when it's set.
+		if err := cutOptions(min(len(args), 2)); err != nil {
+			return nil, err
+		}
+		if len(args) < 1 {
+			return nil, fmt.Errorf("evaluate action expects at least 1 argument (expression, optional value name and options), got %d: %v", len(args), args)
+		}
+		expression := args[0].ValueString()
+		if len(args) > 1 {
+			valueName = args[1].ValueString()
+		}
+		var opts []chromedp.EvaluateOption
+		encodeJSON := false
+		for i := 2; i < len(args); i++ {
+			switch args[i].ValueString() {
+			case "await":
+				opts = append(opts, evalAwaitPromise)
+			case "json":
+				encodeJSON = true
+			default:
+				if !args[i].IsUnknown() {
+					return nil, argError(i+1, fmt.Errorf(`unknown evaluate option %s, expected "await" or "json"`, args[i]))
+				}
+			}
+		}
+		result := &evaluateOutput{encodeJSON: encodeJSON}
+		outputValue = result
+		dpAction = evaluate(expression, result, opts...)
+	case "screenshot":
+		if err := cutOptions(1); err != nil {
+			return nil, err
+		}
+		if len(args) < 1 {
+			return nil, fmt.Errorf("screenshot action expects at least 1 argument (file path, optional selector and options), got %d: %v", len(args), args)
+		}
+		filename := args[0].ValueString()
+		var opts screenshotOptions
+		// unknown options may set the format, so the quality is checked only if all options are known.
+		unknown := false
+		for i := 1; i < len(args); i++ {
+			if args[i].IsUnknown() {
+				unknown = true
+				continue
+			}
+			ok, err := opts.parse(args[i].ValueString())
+			if err != nil {
+				return nil, argError(i+1, err)
+			}
+			if !ok {
+				if i != 1 {
+					return nil, argError(i+1, fmt.Errorf("unknown screenshot option %s, only the argument after file path can be selector", args[i]))
+				}
+				opts.selector = args[i].ValueString()
+			}
+		}
+		if opts.selector != "" && opts.fullPage {
+			return nil, fmt.Errorf("screenshot action can't capture both the element and the full page")
+		}
+		if opts.quality != 0 && !unknown && !hasQuality(opts.format) {
+			return nil, fmt.Errorf(`screenshot action accepts quality option only with "format=jpeg" or "format=webp"`)
+		}
+		dpAction = saveScreenshot(filename, opts)
+	case "storage_get":
+		if err := cutOptions(3); err != nil {
+			return nil, err
+		}
+		if len(args) != 3 {
+			return nil, fmt.Errorf("storage_get action expects 3 arguments (storage, key and value name), got %d: %v", len(args), args)
+		}
+		storage, err := parseWebStorageArg(args[0])
+		if err != nil {
+			return nil, argError(1, err)
+		}
+		key := args[1].ValueString()
+		valueName = args[2].ValueString()
+		item := new(string)
+		outputValue = item
+		dpAction = storageGet(storage, key, item)
+	case "storage_set":
+		if err := cutOptions(3); err != nil {
+			return nil, err
+		}
+		if len(args) != 3 {
+			return nil, fmt.Errorf("storage_set action expects 3 arguments (storage, key and value), got %d: %v", len(args), args)
+		}
+		storage, err := parseWebStorageArg(args[0])
+		if err != nil {
+			return nil, argError(1, err)
+		}
+		dpAction = storageSet(storage, args[1].ValueString(), args[2].ValueString())
+	case "storage_remove":
+		if err := cutOptions(2); err != nil {
+			return nil, err
+		}
+		if len(args) != 2 {
+			return nil, fmt.Errorf("storage_remove action expects 2 arguments (storage and key), got %d: %v", len(args), args)
+		}
+		storage, err := parseWebStorageArg(args[0])
+		if err != nil {
+			return nil, argError(1, err)
+		}
+		dpAction = storageRemove(storage, args[1].ValueString())
+	case "storage_clear":
+		if err := cutOptions(1); err != nil {
+			return nil, err
+		}
+		if len(args) != 1 {
+			return nil, fmt.Errorf("storage_clear action expects 1 argument (storage), got %d: %v", len(args), args)
+		}
+		storage, err := parseWebStorageArg(args[0])
+		if err != nil {
+			return nil, argError(1, err)
+		}
+		dpAction = storageClear(storage)
+	case ifPresentVerb, ifVisibleVerb, forEachNodeVerb:
+		if err := cutOptions(1); err != nil {
+			return nil, err
+		}
+		if len(args) != 1 {
+			return nil, fmt.Errorf("%s action expects only 1 argument (selector), got %d: %v", verb.ValueString(), len(args), args)
+		}
+		if common.sensitive {
+			// Blocks catch no values, so the option would only hide the selector.
+			return nil, fmt.Errorf("%s action doesn't accept sensitive option", verb.ValueString())
+		}
+		block := &actionBlock{verb: verb.ValueString(), selector: args[0].ValueString()}
+		action := NewAction(block.action(), "", nil)
+		action.block = block
+		action.timeout = common.timeout
+		action.retry = common.retry
+		return action, nil
+	case endVerb:
+		if err := cutOptions(0); err != nil {
+			return nil, err
+		}
+		if len(args) != 0 {
+			return nil, fmt.Errorf("end action expects 0 arguments, got %d: %v", len(args), args)
+		}
+		if common.sensitive || common.timeout != 0 || common.retry.attempts != 0 || common.retry.backoff != 0 || len(common.retry.on) != 0 {
+			return nil, fmt.Errorf("end action doesn't accept options")
+		}
+		action := NewAction(nil, "", nil)
+		action.blockEnd = true
+		return action, nil
 	default:
-		return nil, fmt.Errorf("unknown action: %s", verb)
+		return nil, argError(0, fmt.Errorf("unknown action: %s", verb))
 	}
-	return NewAction(dpAction, valueName, outputValue), nil
+	action := NewAction(dpAction, valueName, outputValue)
+	action.timeout = common.timeout
+	action.retry = common.retry
+	action.sensitive = common.sensitive
+	return action, nil
+}
+
+func evalAwaitPromise(p *runtime.EvaluateParams) *runtime.EvaluateParams {
+	return p.WithAwaitPromise(true)
+}
+
+// evaluateOutput is the JSON-encoded result of the evaluation.
+type evaluateOutput struct {
+	raw        []byte
+	encodeJSON bool
+}
+
+// String returns the result for "values" attribute.
+func (o *evaluateOutput) String() string {
+	return evaluateResult(o.raw, o.encodeJSON)
+}
+
+func (o *evaluateOutput) MarshalJSON() ([]byte, error) {
+	if len(o.raw) == 0 {
+		return []byte("null"), nil
+	}
+	return o.raw, nil
+}
+
+// evaluate runs JavaScript expression and places the result into res.
+func evaluate(expression string, res *evaluateOutput, opts ...chromedp.EvaluateOption) chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		return chromedp.Evaluate(expression, &res.raw, opts...).Do(ctx)
+	})
+}
+
+// evaluateResult converts JSON-encoded result of the evaluation into the value.
+// Strings are returned as is unless encodeJSON is set, other types are returned JSON-encoded.
+// Undefined result is returned as empty string or as null if encodeJSON is set.
+func evaluateResult(raw []byte, encodeJSON bool) string {
+	if len(raw) == 0 {
+		if encodeJSON {
+			return "null"
+		}
+		return ""
+	}
+	if !encodeJSON {
+		var str string
+		if json.Unmarshal(raw, &str) == nil {
+			return str
+		}
+	}
+	return string(raw)
+}
+
+const (
+	textContentJS = `function() { return this.textContent; }`
+	valueJS       = `function() { return this.value; }`
+)
+
+// queryAll gets string from every node matching the selector and places them into res.
+// It doesn't wait for nodes, no matching nodes give empty list.
+func queryAll(selector string, res *[]string, get func(ctx context.Context, node *cdp.Node) (string, error)) chromedp.Action {
+	return chromedp.QueryAfter(selector, func(ctx context.Context, _ runtime.ExecutionContextID, nodes ...*cdp.Node) error {
+		list := make([]string, 0, len(nodes))
+		for _, node := range nodes {
+			v, err := get(ctx, node)
+			if err != nil {
+				return err
+			}
+			list = append(list, v)
+		}
+		*res = list
+		return nil
+	}, chromedp.ByQueryAll, chromedp.AtLeast(0))
+}
+
+// nodeFunction returns getter which calls JavaScript function on the node.
+func nodeFunction(function string) func(ctx context.Context, node *cdp.Node) (string, error) {
+	return func(ctx context.Context, node *cdp.Node) (string, error) {
+		var res string
+		err := callNodeFunction(ctx, node, function, &res)
+		return res, err
+	}
+}
+
+// callNodeFunction calls JavaScript function on the node, the node is passed to the function as this.
+func callNodeFunction(ctx context.Context, node *cdp.Node, function string, res interface{}, args ...interface{}) error {
+	obj, err := dom.ResolveNode().WithNodeID(node.NodeID).Do(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// The object is released with the page anyway, so the error is ignored.
+		_ = runtime.ReleaseObject(obj.ObjectID).Do(ctx)
+	}()
+
+	return chromedp.CallFunctionOn(function, res, func(p *runtime.CallFunctionOnParams) *runtime.CallFunctionOnParams {
+		return p.WithObjectID(obj.ObjectID)
+	}, args...).Do(ctx)
 }
@@ -2,8 +2,13 @@ package provider
 
 import (
 	"testing"
+	"time"
 
 	"github.com/chromedp/chromedp"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -12,7 +17,7 @@ func TestNewActionValue(t *testing.T) {
 	act := NewAction(chromedp.Value("body", value), "value", value)
 	assert.NotNil(t, act)
 
-	m := map[string]*string{}
+	m := map[string]interface{}{}
 
 	_ = act.Action(m)
 
@@ -22,3 +27,219 @@ func TestNewActionValue(t *testing.T) {
 func TestAction_Action(t *testing.T) {
 
 }
+
+func testActionsList(t *testing.T, actions ...[]attr.Value) types.List {
+	t.Helper()
+	elems := make([]attr.Value, 0, len(actions))
+	for _, args := range actions {
+		elems = append(elems, types.ListValueMust(types.StringType, args))
+	}
+	return types.ListValueMust(types.ListType{ElemType: types.StringType}, elems)
+}
+
+func TestValidateActions(t *testing.T) {
+	actions := testActionsList(t,
+		[]attr.Value{types.StringValue("navigate"), types.StringValue("https://example.com")},
+		[]attr.Value{types.StringValue("sleep"), types.StringValue("3 seconds")},
+		[]attr.Value{types.StringValue("navigat"), types.StringValue("https://example.com")},
+		[]attr.Value{types.StringValue("wait_visible")},
+		[]attr.Value{types.StringValue("sleep"), types.StringUnknown()},
+		[]attr.Value{types.StringUnknown(), types.StringValue("anything")},
+	)
+
+	diags := validateActions(actions, path.Root("actions"))
+	assert.Equal(t, 3, diags.ErrorsCount())
+
+	var paths []path.Path
+	for _, d := range diags.Errors() {
+		withPath, ok := d.(diag.DiagnosticWithPath)
+		assert.True(t, ok)
+		paths = append(paths, withPath.Path())
+	}
+	assert.Equal(t, []path.Path{
+		path.Root("actions").AtListIndex(1).AtListIndex(1),
+		path.Root("actions").AtListIndex(2).AtListIndex(0),
+		path.Root("actions").AtListIndex(3),
+	}, paths)
+
+	assert.False(t, validateActions(types.ListUnknown(types.ListType{ElemType: types.StringType}), path.Root("actions")).HasError())
+}
+
+func TestEvaluateResult(t *testing.T) {
+	assert.Equal(t, "text", evaluateResult([]byte(`"text"`), false))
+	assert.Equal(t, `"text"`, evaluateResult([]byte(`"text"`), true))
+	assert.Equal(t, `{"a":[1,2]}`, evaluateResult([]byte(`{"a":[1,2]}`), false))
+	assert.Equal(t, "42", evaluateResult([]byte(`42`), false))
+	assert.Equal(t, "", evaluateResult(nil, false))
+	assert.Equal(t, "null", evaluateResult(nil, true))
+}
+
+func TestActionBuilderEvaluate(t *testing.T) {
+	_, err := actionBuilder([]types.String{types.StringValue("evaluate"), types.StringValue("window.__APP_CONFIG__"), types.StringValue("config"), types.StringValue("await"), types.StringValue("json")})
+	assert.NoError(t, err)
+
+	_, err = actionBuilder([]types.String{types.StringValue("evaluate"), types.StringValue("1"), types.StringValue("one"), types.StringValue("wait")})
+	var argErr *actionArgError
+	assert.ErrorAs(t, err, &argErr)
+	assert.Equal(t, 3, argErr.index)
+}
+
+func TestActionBuilderAll(t *testing.T) {
+	values := map[string]interface{}{}
+
+	for _, args := range [][]types.String{
+		{types.StringValue("text_all"), types.StringValue("td.version"), types.StringValue("versions")},
+		{types.StringValue("value_all"), types.StringValue("input"), types.StringValue("inputs")},
+		{types.StringValue("attribute_all"), types.StringValue("a"), types.StringValue("href"), types.StringValue("links")},
+	} {
+		action, err := actionBuilder(args)
+		assert.NoError(t, err)
+		_ = action.Action(values)
+	}
+
+	strValues, _, err := outputValues(values)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"versions": "[]", "inputs": "[]", "links": "[]"}, strValues)
+
+	_, err = actionBuilder([]types.String{types.StringValue("attribute_all"), types.StringValue("a"), types.StringValue("links")})
+	assert.Error(t, err)
+}
+
+func TestActionBuilderAttributes(t *testing.T) {
+	for _, args := range [][]string{
+		{"attribute", "a", "href", "link"},
+		{"attributes", "a", "link_attributes"},
+		{"set_attribute", "#submit", "data-confirmed", "true"},
+		{"remove_attribute", "#submit", "disabled"},
+	} {
+		_, err := actionBuilder(testArgs(args...))
+		assert.NoError(t, err, args)
+
+		_, err = actionBuilder(testArgs(args[:len(args)-1]...))
+		assert.Error(t, err, args)
+	}
+}
+
+func TestActionBuilderStorage(t *testing.T) {
+	for _, args := range [][]string{
+		{"storage_get", "local", "flags", "flags"},
+		{"storage_set", "session", "token", "secret"},
+		{"storage_remove", "local", "flags"},
+		{"storage_clear", "session"},
+	} {
+		action, err := actionBuilder(testArgs(args...))
+		assert.NoError(t, err, args)
+		assert.NotNil(t, action, args)
+
+		_, err = actionBuilder(testArgs(args[:len(args)-1]...))
+		assert.Error(t, err, args)
+
+		invalid := append([]string{args[0], "cookies"}, args[2:]...)
+		_, err = actionBuilder(testArgs(invalid...))
+		var argErr *actionArgError
+		if assert.ErrorAs(t, err, &argErr, args) {
+			assert.Equal(t, 1, argErr.index)
+		}
+	}
+
+	args := testArgs("storage_clear", "local")
+	args[1] = types.StringUnknown()
+	_, err := actionBuilder(args)
+	assert.NoError(t, err)
+}
+
+func TestActionBuilderSensitive(t *testing.T) {
+	action, err := actionBuilder(testArgs("text", "#api-token", "token", "timeout=10s", "sensitive"))
+	assert.NoError(t, err)
+	assert.True(t, action.sensitive)
+	assert.Equal(t, 10*time.Second, action.timeout)
+
+	values := map[string]interface{}{}
+	action.Action(values)
+	assert.IsType(t, sensitiveOutput{}, values["token"])
+
+	action, err = actionBuilder(testArgs("text", "#title", "title"))
+	assert.NoError(t, err)
+	assert.False(t, action.sensitive)
+	action.Action(values)
+	assert.IsType(t, new(string), values["title"])
+
+	// Required arguments are never taken for the option, so the word can be typed.
+	for _, args := range [][]string{
+		{"send_keys", "#q", "sensitive"},
+		{"set_value", "#f", "sensitive"},
+		{"text", "#title", "sensitive"},
+	} {
+		action, err = actionBuilder(testArgs(args...))
+		if assert.NoError(t, err, args) {
+			assert.False(t, action.sensitive, args)
+		}
+	}
+	action, err = actionBuilder(testArgs("send_keys", "#q", "sensitive", "sensitive"))
+	assert.NoError(t, err)
+	assert.True(t, action.sensitive)
+
+	// The optional value name of evaluate is taken like required arguments when it's set.
+	action, err = actionBuilder(testArgs("evaluate", "document.title", "sensitive"))
+	if assert.NoError(t, err) {
+		assert.False(t, action.sensitive)
+		assert.Equal(t, "sensitive", action.valueName)
+	}
+	action, err = actionBuilder(testArgs("evaluate", "document.title", "title", "json", "sensitive"))
+	if assert.NoError(t, err) {
+		assert.True(t, action.sensitive)
+		assert.Equal(t, "title", action.valueName)
+	}
+	action, err = actionBuilder(testArgs("evaluate", "document.title", "", "timeout=5s"))
+	if assert.NoError(t, err) {
+		assert.Equal(t, 5*time.Second, action.timeout)
+		assert.Empty(t, action.valueName)
+	}
+
+	for _, args := range [][]string{
+		{"if_present", "#banner", "sensitive"},
+		{"for_each_node", "li", "sensitive"},
+		{"end", "sensitive"},
+		{"end", "timeout=5s"},
+	} {
+		_, err = actionBuilder(testArgs(args...))
+		assert.Error(t, err, args)
+	}
+}
+
+func TestActionBuilderTimeout(t *testing.T) {
+	action, err := actionBuilder(testArgs("wait_visible", "body footer", "timeout=10s"))
+	assert.NoError(t, err)
+	assert.Equal(t, 10*time.Second, action.timeout)
+
+	action, err = actionBuilder(testArgs("wait_visible", "body footer"))
+	assert.NoError(t, err)
+	assert.Zero(t, action.timeout)
+
+	// Required arguments are never taken for the option.
+	action, err = actionBuilder(testArgs("wait_visible", "timeout=10s"))
+	assert.NoError(t, err)
+	assert.Zero(t, action.timeout)
+
+	action, err = actionBuilder(testArgs("send_keys", "#q", "timeout=5"))
+	assert.NoError(t, err)
+	assert.Zero(t, action.timeout)
+
+	action, err = actionBuilder(testArgs("set_value", "#f", "timeout=30s", "timeout=5s"))
+	assert.NoError(t, err)
+	assert.Equal(t, 5*time.Second, action.timeout)
+
+	_, err = actionBuilder(testArgs("wait_visible", "body footer", "timeout=soon"))
+	var argErr *actionArgError
+	if assert.ErrorAs(t, err, &argErr) {
+		assert.Equal(t, 2, argErr.index)
+	}
+}
+
+func testArgs(args ...string) []types.String {
+	res := make([]types.String, 0, len(args))
+	for _, arg := range args {
+		res = append(res, types.StringValue(arg))
+	}
+	return res
+}
@@ -0,0 +1,190 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/chromedp/chromedp"
+)
+
+// openPools tracks started pools, so browsers can be closed when the provider process exits.
+var openPools = struct {
+	sync.Mutex
+	pools map[*browserPool]struct{}
+}{pools: map[*browserPool]struct{}{}}
+
+// CloseBrowsers closes all browsers started by the provider. It must be called before the provider process exits.
+func CloseBrowsers() {
+	openPools.Lock()
+	pools := make([]*browserPool, 0, len(openPools.pools))
+	for pool := range openPools.pools {
+		pools = append(pools, pool)
+	}
+	openPools.Unlock()
+
+	for _, pool := range pools {
+		pool.close()
+	}
+}
+
+type browserPoolOptions struct {
+	// name describes the browser in diagnostics.
+	name string
+	// maxParallelTabs limits number of tabs opened in parallel, 0 means no limit.
+	maxParallelTabs int
+	// connectTimeout limits time of launching or connecting to the browser, 0 means no limit.
+	connectTimeout time.Duration
+	// skipPing disables navigation to blank page right after the connection.
+	skipPing bool
+}
+
+// browserPool owns the browser shared by all recipes of the provider.
+// The browser is launched or connected lazily when the first tab is requested.
+// Every recipe runs in its own tab with separate browser context, so cookies and storage are not shared between recipes.
+type browserPool struct {
+	allocator allocatorFunc
+	opts      browserPoolOptions
+	// tabs limits number of tabs opened in parallel, nil means no limit.
+	tabs chan struct{}
+
+	mu         sync.Mutex
+	browserCtx context.Context
+	cancel     context.CancelFunc
+	// err is the error of the failed start. It's returned to all following callers instead of connecting again.
+	err error
+}
+
+func newBrowserPool(allocator allocatorFunc, opts browserPoolOptions) *browserPool {
+	pool := &browserPool{
+		allocator: allocator,
+		opts:      opts,
+	}
+	if opts.maxParallelTabs > 0 {
+		pool.tabs = make(chan struct{}, opts.maxParallelTabs)
+	}
+	return pool
+}
+
+// start launches or connects to the browser unless it's already done.
+func (p *browserPool) start(ctx context.Context) (context.Context, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.browserCtx != nil {
+		return p.browserCtx, nil
+	}
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	// The browser outlives requests of Terraform, so it isn't derived from the request context.
+	allocatorCtx, allocatorCancel := p.allocator(context.Background())
+	browserCtx, browserCancel := chromedp.NewContext(allocatorCtx)
+	cancel := func() {
+		browserCancel()
+		allocatorCancel()
+	}
+
+	errc := make(chan error, 1)
+	go func() {
+		err := chromedp.Run(browserCtx)
+		if err == nil && !p.opts.skipPing {
+			_, err = pingChrome(browserCtx)
+		}
+		errc <- err
+	}()
+
+	var timeout <-chan time.Time
+	if p.opts.connectTimeout > 0 {
+		timer := time.NewTimer(p.opts.connectTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	var err error
+	select {
+	case err = <-errc:
+	case <-timeout:
+		cancel()
+		<-errc
+		err = fmt.Errorf("timed out after %s", p.opts.connectTimeout)
+	case <-ctx.Done():
+		// The request is cancelled, so the next caller tries to connect again.
+		cancel()
+		<-errc
+		return nil, ctx.Err()
+	}
+	if err != nil {
+		cancel()
+		p.err = fmt.Errorf("can't connect to %s: %w", p.opts.name, err)
+		return nil, p.err
+	}
+
+	p.browserCtx = browserCtx
+	p.cancel = cancel
+
+	openPools.Lock()
+	openPools.pools[p] = struct{}{}
+	openPools.Unlock()
+
+	return browserCtx, nil
+}
+
+// newTab opens the tab in new browser context. The tab is closed when release is called or ctx is done.
+func (p *browserPool) newTab(ctx context.Context) (tabCtx context.Context, release context.CancelFunc, err error) {
+	browserCtx, err := p.start(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if p.tabs != nil {
+		select {
+		case p.tabs <- struct{}{}:
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		}
+	}
+
+	tabCtx, tabCancel := chromedp.NewContext(browserCtx, chromedp.WithNewBrowserContext())
+
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			tabCancel()
+		case <-done:
+		}
+	}()
+
+	var once sync.Once
+	release = func() {
+		once.Do(func() {
+			close(done)
+			tabCancel()
+			if p.tabs != nil {
+				<-p.tabs
+			}
+		})
+	}
+	return tabCtx, release, nil
+}
+
+// close closes the browser. The pool can be started again afterwards.
+func (p *browserPool) close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.err = nil
+	if p.cancel == nil {
+		return
+	}
+	p.cancel()
+	p.cancel = nil
+	p.browserCtx = nil
+
+	openPools.Lock()
+	delete(openPools.pools, p)
+	openPools.Unlock()
+}
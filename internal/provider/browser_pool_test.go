@@ -0,0 +1,52 @@
+package provider
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/chromedp/chromedp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBrowserPool_newTabLimit(t *testing.T) {
+	allocator := remoteChromeAllocator("ws://127.0.0.1:1")
+	pool := newBrowserPool(allocator, browserPoolOptions{name: "test", maxParallelTabs: 1})
+	// Pretend the browser is started, tabs are not connected until the first action runs.
+	allocatorCtx, allocatorCancel := allocator(context.Background())
+	defer allocatorCancel()
+	pool.browserCtx, pool.cancel = chromedp.NewContext(allocatorCtx)
+	defer pool.close()
+
+	_, release, err := pool.newTab(context.Background())
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, _, err = pool.newTab(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	release()
+	release()
+
+	_, release, err = pool.newTab(context.Background())
+	assert.NoError(t, err)
+	release()
+}
+
+func TestBrowserPool_startError(t *testing.T) {
+	pool := newBrowserPool(remoteChromeAllocator("ws://127.0.0.1:1"), browserPoolOptions{name: "test", connectTimeout: time.Second})
+
+	_, _, err := pool.newTab(context.Background())
+	assert.ErrorContains(t, err, "can't connect to test")
+	assert.Nil(t, pool.browserCtx)
+
+	// The error is remembered and the connection isn't retried.
+	_, _, errAgain := pool.newTab(context.Background())
+	assert.Same(t, err, errAgain)
+
+	openPools.Lock()
+	_, ok := openPools.pools[pool]
+	openPools.Unlock()
+	assert.False(t, ok)
+}
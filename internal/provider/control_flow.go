@@ -0,0 +1,156 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/dom"
+	"github.com/chromedp/chromedp"
+)
+
+// Verbs of actions opening blocks of nested actions. Every block is closed by "end" action.
+const (
+	ifPresentVerb   = "if_present"
+	ifVisibleVerb   = "if_visible"
+	forEachNodeVerb = "for_each_node"
+	endVerb         = "end"
+)
+
+// errEndWithoutBlock is returned for "end" action which doesn't close any block.
+var errEndWithoutBlock = errors.New("end action doesn't close any if_present, if_visible or for_each_node action")
+
+// unclosedBlockError is returned for block actions without their "end" action.
+func unclosedBlockError(verb string) error {
+	return fmt.Errorf(`%s action isn't closed by ["end"] action`, verb)
+}
+
+// inNodeLoop reports whether actions nested in the opened blocks run for nodes of for_each_node action.
+func inNodeLoop(opened []actionDefinition) bool {
+	for _, def := range opened {
+		if def.args[0].ValueString() == forEachNodeVerb {
+			return true
+		}
+	}
+	return false
+}
+
+// nodeMarkAttribute marks nodes iterated by for_each_node, so nested actions select them with "${node}" placeholder.
+const nodeMarkAttribute = "data-chromedp-node"
+
+// nodeLoops numbers runs of for_each_node actions, so marks of nested loops don't collide.
+var nodeLoops atomic.Int64
+
+// isNodeVisibleFunction reports whether the element has a layout box and isn't hidden by CSS visibility.
+const isNodeVisibleFunction = `function() {
+	const style = window.getComputedStyle(this);
+	return style.visibility !== 'hidden' && !!(this.offsetWidth || this.offsetHeight || this.getClientRects().length);
+}`
+
+// nodeScope is the node iterated by for_each_node action.
+type nodeScope struct {
+	// selector selects only the node.
+	selector string
+	// index is the position of the node among nodes matching the selector of the loop.
+	index int
+}
+
+type nodeScopeKey struct{}
+
+// withNodeScope returns ctx of nested actions run for the node.
+func withNodeScope(ctx context.Context, scope *nodeScope) context.Context {
+	if scope == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, nodeScopeKey{}, scope)
+}
+
+// nodeScopeFrom returns the node of the innermost for_each_node action running the ctx, nil outside of loops.
+func nodeScopeFrom(ctx context.Context) *nodeScope {
+	scope, _ := ctx.Value(nodeScopeKey{}).(*nodeScope)
+	return scope
+}
+
+// actionBlock is the block opened by if_present, if_visible or for_each_node action.
+// The action of the block queries nodes and sets scopes, nested actions run once per scope after it.
+type actionBlock struct {
+	verb     string
+	selector string
+	// values are caught values replacing placeholders in the selector when the block runs, nil if it has no placeholders.
+	values map[string]interface{}
+	// scopes are set when the action of the block runs: no scopes skip nested actions,
+	// if_* blocks keep the node of the enclosing loop, for_each_node has scope of every node.
+	scopes []*nodeScope
+}
+
+// queryNodes gets all element nodes matching the CSS selector without waiting for them.
+// Unlike the default search of chromedp, it doesn't match text and attribute values of nodes.
+func queryNodes(ctx context.Context, selector string) ([]*cdp.Node, error) {
+	var nodes []*cdp.Node
+	if err := chromedp.Nodes(selector, &nodes, chromedp.ByQueryAll, chromedp.AtLeast(0)).Do(ctx); err != nil {
+		return nil, err
+	}
+	return nodes, nil
+}
+
+// resolveSelector replaces placeholders in the selector of the block with caught values and the iterated node.
+func (b *actionBlock) resolveSelector(ctx context.Context) (string, error) {
+	if b.values == nil {
+		return b.selector, nil
+	}
+	selector, err := interpolateNode(b.selector, nodeScopeFrom(ctx))
+	if err != nil {
+		return "", err
+	}
+	return interpolateValues(selector, b.values)
+}
+
+// action returns the action evaluating the block.
+func (b *actionBlock) action() chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		b.scopes = nil
+		selector, err := b.resolveSelector(ctx)
+		if err != nil {
+			return err
+		}
+		nodes, err := queryNodes(ctx, selector)
+		if err != nil {
+			return err
+		}
+
+		switch b.verb {
+		case ifPresentVerb:
+			if len(nodes) > 0 {
+				b.scopes = []*nodeScope{nodeScopeFrom(ctx)}
+			}
+		case ifVisibleVerb:
+			for _, node := range nodes {
+				var visible bool
+				if err := callNodeFunction(ctx, node, isNodeVisibleFunction, &visible); err != nil {
+					return err
+				}
+				if visible {
+					b.scopes = []*nodeScope{nodeScopeFrom(ctx)}
+					break
+				}
+			}
+		case forEachNodeVerb:
+			loop := nodeLoops.Add(1)
+			scopes := make([]*nodeScope, 0, len(nodes))
+			for i, node := range nodes {
+				mark := fmt.Sprintf("%d-%d", loop, i)
+				if err := dom.SetAttributeValue(node.NodeID, nodeMarkAttribute, mark).Do(ctx); err != nil {
+					return fmt.Errorf("can't mark node %d: %w", i, err)
+				}
+				scopes = append(scopes, &nodeScope{
+					selector: fmt.Sprintf(`[%s=%s]`, nodeMarkAttribute, jsString(mark)),
+					index:    i,
+				})
+			}
+			b.scopes = scopes
+		}
+		return nil
+	})
+}
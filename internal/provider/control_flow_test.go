@@ -0,0 +1,162 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActionBuilderBlocks(t *testing.T) {
+	for _, verb := range []string{"if_present", "if_visible", "for_each_node"} {
+		action, err := actionBuilder(testArgs(verb, "#cookie-banner", "timeout=5s"))
+		if assert.NoError(t, err, verb) && assert.NotNil(t, action.block, verb) {
+			assert.Equal(t, verb, action.block.verb)
+			assert.Equal(t, "#cookie-banner", action.block.selector)
+		}
+
+		_, err = actionBuilder(testArgs(verb))
+		assert.ErrorContains(t, err, "expects only 1 argument", verb)
+	}
+
+	action, err := actionBuilder(testArgs("end"))
+	assert.NoError(t, err)
+	assert.True(t, action.blockEnd)
+
+	_, err = actionBuilder(testArgs("end", "#cookie-banner"))
+	assert.ErrorContains(t, err, "end action expects 0 arguments")
+}
+
+func TestBuildActionsBlocks(t *testing.T) {
+	var defs []actionDefinition
+	for i, args := range [][]string{
+		{"navigate", "about:blank"},
+		{"if_visible", "#accept-cookies"},
+		{"click", "#accept-cookies"},
+		{"end"},
+		{"for_each_node", "ul > li"},
+		{"if_present", "${node} .version"},
+		{"text", "${node} .version", "version_${node.index}"},
+		{"end"},
+		{"end"},
+		{"text", "h1", "title"},
+	} {
+		defs = append(defs, actionDefinition{args: testArgs(args...), path: path.Root("actions").AtListIndex(i), positional: true})
+	}
+
+	steps, diags := buildActions(context.Background(), defs, map[string]interface{}{})
+	assert.False(t, diags.HasError(), diags)
+	if !assert.Len(t, steps, 4) {
+		return
+	}
+	assert.Equal(t, `actions[1]: if_visible "#accept-cookies"`, steps[1].name)
+	if assert.Len(t, steps[1].children, 1) {
+		assert.Equal(t, `actions[2]: click "#accept-cookies"`, steps[1].children[0].name)
+	}
+	loop := steps[2]
+	assert.Equal(t, "for_each_node", loop.block.verb)
+	if assert.Len(t, loop.children, 1) && assert.Len(t, loop.children[0].children, 1) {
+		assert.Equal(t, "${node} .version", loop.children[0].block.selector)
+		assert.NotNil(t, loop.children[0].block.values)
+		assert.True(t, loop.children[0].children[0].path.Equal(path.Root("actions").AtListIndex(6)))
+	}
+	assert.Nil(t, steps[3].block)
+	assert.Empty(t, steps[3].children)
+}
+
+func TestBuildActionsBlocks_errors(t *testing.T) {
+	defs := []actionDefinition{
+		{args: testArgs("end"), path: path.Root("actions").AtListIndex(0), positional: true},
+		{args: testArgs("text", "${node}", "outside"), path: path.Root("actions").AtListIndex(1), positional: true},
+		{args: testArgs("if_present", "#cookie-banner"), path: path.Root("actions").AtListIndex(2), positional: true},
+	}
+
+	_, diags := buildActions(context.Background(), defs, map[string]interface{}{})
+	if !assert.Equal(t, 3, diags.ErrorsCount()) {
+		return
+	}
+	assert.Contains(t, diags[0].Detail(), "end action doesn't close any")
+	assert.True(t, diags[0].(diag.DiagnosticWithPath).Path().Equal(path.Root("actions").AtListIndex(0)))
+	assert.Contains(t, diags[1].Detail(), "${node} placeholder is used outside of for_each_node action")
+	assert.True(t, diags[1].(diag.DiagnosticWithPath).Path().Equal(path.Root("actions").AtListIndex(1).AtListIndex(1)))
+	assert.Contains(t, diags[2].Detail(), `if_present action isn't closed by ["end"] action`)
+	assert.True(t, diags[2].(diag.DiagnosticWithPath).Path().Equal(path.Root("actions").AtListIndex(2)))
+}
+
+func TestValidateActionsBlocks(t *testing.T) {
+	actions := testActionsList(t,
+		[]attr.Value{types.StringValue("for_each_node"), types.StringValue("li")},
+		[]attr.Value{types.StringValue("text"), types.StringValue("${node}"), types.StringValue("item_${node.index}")},
+		[]attr.Value{types.StringValue("end")},
+		[]attr.Value{types.StringValue("end")},
+		[]attr.Value{types.StringValue("if_present"), types.StringValue("#cookie-banner")},
+	)
+
+	diags := validateActions(actions, path.Root("actions"))
+	var paths []path.Path
+	for _, d := range diags.Errors() {
+		paths = append(paths, d.(diag.DiagnosticWithPath).Path())
+	}
+	assert.Equal(t, []path.Path{
+		path.Root("actions").AtListIndex(3),
+		path.Root("actions").AtListIndex(4),
+	}, paths)
+
+	// Unknown actions may close blocks, so blocks aren't checked.
+	actions = testActionsList(t,
+		[]attr.Value{types.StringValue("if_present"), types.StringValue("#cookie-banner")},
+		[]attr.Value{types.StringUnknown()},
+	)
+	assert.False(t, validateActions(actions, path.Root("actions")).HasError())
+}
+
+func TestStepModel_actionArgsBlocks(t *testing.T) {
+	args, err := StepModel{ForEachNode: &SelectorStepModel{Selector: types.StringValue("li")}}.actionArgs()
+	assert.NoError(t, err)
+	assert.Equal(t, testArgs("for_each_node", "li"), args)
+
+	args, err = StepModel{End: &EmptyStepModel{}}.actionArgs()
+	assert.NoError(t, err)
+	assert.Equal(t, testArgs("end"), args)
+}
+
+func TestNodeScope(t *testing.T) {
+	ctx := context.Background()
+	assert.Nil(t, nodeScopeFrom(ctx))
+	assert.Equal(t, ctx, withNodeScope(ctx, nil))
+
+	scope := &nodeScope{selector: `[data-chromedp-node="1-2"]`, index: 2}
+	assert.Equal(t, scope, nodeScopeFrom(withNodeScope(ctx, scope)))
+}
+
+func TestValidateStepsBlocks(t *testing.T) {
+	elemType := stepsAttribute().NestedObject.Type()
+	steps, diags := types.ListValueFrom(context.Background(), elemType, []StepModel{
+		{Sleep: &SleepStepModel{Duration: types.StringValue("3 seconds")}},
+		{ForEachNode: &SelectorStepModel{Selector: types.StringValue("li")}},
+		{Text: &CaptureStepModel{Selector: types.StringValue("${node}"), Name: types.StringValue("item_${node.index}")}},
+		{End: &EmptyStepModel{}},
+		{End: &EmptyStepModel{}},
+		{IfPresent: &SelectorStepModel{Selector: types.StringValue("#cookie-banner")}},
+		{Text: &CaptureStepModel{Selector: types.StringValue("${node} .version"), Name: types.StringValue("version")}},
+	})
+	if !assert.False(t, diags.HasError(), diags) {
+		return
+	}
+
+	diags = validateSteps(context.Background(), steps, path.Root("steps"))
+	var paths []path.Path
+	for _, d := range diags.Errors() {
+		paths = append(paths, d.(diag.DiagnosticWithPath).Path())
+	}
+	assert.Equal(t, []path.Path{
+		path.Root("steps").AtListIndex(0),
+		path.Root("steps").AtListIndex(4),
+		path.Root("steps").AtListIndex(6),
+		path.Root("steps").AtListIndex(5),
+	}, paths)
+}
@@ -0,0 +1,178 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+)
+
+// defaultCookieTTL is the lifetime of the cookie set without expiration options.
+const defaultCookieTTL = 24 * time.Hour
+
+type cookieOptions struct {
+	domain   string
+	path     string
+	url      string
+	secure   bool
+	httpOnly bool
+	sameSite network.CookieSameSite
+	// session cookies have no expiration date and are deleted when the browser context is closed.
+	session bool
+	expires time.Time
+	maxAge  time.Duration
+}
+
+// parse parses option of cookie actions. It returns false if opt isn't a cookie option.
+func (o *cookieOptions) parse(opt string) (bool, error) {
+	switch opt {
+	case "secure":
+		o.secure = true
+		return true, nil
+	case "http_only":
+		o.httpOnly = true
+		return true, nil
+	case "session":
+		o.session = true
+		return true, nil
+	}
+
+	key, value, found := strings.Cut(opt, "=")
+	if !found {
+		return false, nil
+	}
+
+	switch key {
+	case "domain":
+		o.domain = value
+	case "path":
+		o.path = value
+	case "url":
+		o.url = value
+	case "same_site":
+		switch sameSite := network.CookieSameSite(value); sameSite {
+		case network.CookieSameSiteStrict, network.CookieSameSiteLax, network.CookieSameSiteNone:
+			o.sameSite = sameSite
+		default:
+			return true, fmt.Errorf("same_site cookie option must be Strict, Lax or None, got %q", value)
+		}
+	case "expires":
+		expires, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return true, fmt.Errorf("expires cookie option must be RFC 3339 timestamp: %w", err)
+		}
+		o.expires = expires
+	case "max_age":
+		maxAge, err := time.ParseDuration(value)
+		if err != nil {
+			return true, fmt.Errorf("max_age cookie option must be duration: %w", err)
+		}
+		o.maxAge = maxAge
+	default:
+		return false, nil
+	}
+	return true, nil
+}
+
+// parseScope parses option which narrows cookies down: "domain=", "path=" or "url=".
+func (o *cookieOptions) parseScope(opt string) error {
+	key, _, _ := strings.Cut(opt, "=")
+	switch key {
+	case "domain", "path", "url":
+		_, err := o.parse(opt)
+		return err
+	}
+	return fmt.Errorf(`unknown cookie option %q, expected "domain=<domain>", "path=<path>" or "url=<url>"`, opt)
+}
+
+// validate checks that options don't contradict each other.
+func (o cookieOptions) validate() error {
+	expirations := 0
+	if o.session {
+		expirations++
+	}
+	if !o.expires.IsZero() {
+		expirations++
+	}
+	if o.maxAge != 0 {
+		expirations++
+	}
+	if expirations > 1 {
+		return fmt.Errorf("only one of session, expires and max_age cookie options can be set")
+	}
+	return nil
+}
+
+// setCookie sets the cookie. Cookies expire in 24 hours unless other expiration is set in options.
+func setCookie(name, value string, opts cookieOptions) chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		params := network.SetCookie(name, value)
+		if !opts.session {
+			expires := time.Now().Add(defaultCookieTTL)
+			switch {
+			case !opts.expires.IsZero():
+				expires = opts.expires
+			case opts.maxAge != 0:
+				expires = time.Now().Add(opts.maxAge)
+			}
+			expr := cdp.TimeSinceEpoch(expires)
+			params = params.WithExpires(&expr)
+		}
+		if opts.domain != "" {
+			params = params.WithDomain(opts.domain)
+		}
+		if opts.path != "" {
+			params = params.WithPath(opts.path)
+		}
+		if opts.url != "" {
+			params = params.WithURL(opts.url)
+		}
+		if opts.secure {
+			params = params.WithSecure(true)
+		}
+		if opts.httpOnly {
+			params = params.WithHTTPOnly(true)
+		}
+		if opts.sameSite != "" {
+			params = params.WithSameSite(opts.sameSite)
+		}
+		return params.Do(ctx)
+	})
+}
+
+// getCookies gets cookies for the URLs or for the current page if no URLs are given.
+func getCookies(urls []string, res *[]*network.Cookie) chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		params := network.GetCookies()
+		if len(urls) > 0 {
+			params = params.WithUrls(urls)
+		}
+		cookies, err := params.Do(ctx)
+		if err != nil {
+			return err
+		}
+		*res = cookies
+		return nil
+	})
+}
+
+// deleteCookie deletes cookies matching the name and domain, path and url options.
+func deleteCookie(name string, opts cookieOptions) chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		params := network.DeleteCookies(name)
+		if opts.domain != "" {
+			params = params.WithDomain(opts.domain)
+		}
+		if opts.path != "" {
+			params = params.WithPath(opts.path)
+		}
+		if opts.url != "" {
+			params = params.WithURL(opts.url)
+		}
+		return params.Do(ctx)
+	})
+}
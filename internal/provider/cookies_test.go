@@ -0,0 +1,86 @@
+package provider
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chromedp/cdproto/network"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCookieOptions_parse(t *testing.T) {
+	var opts cookieOptions
+	for _, opt := range []string{"domain=example.com", "path=/app", "url=https://example.com/", "secure", "http_only", "same_site=Lax", "max_age=1h"} {
+		ok, err := opts.parse(opt)
+		assert.True(t, ok, opt)
+		assert.NoError(t, err, opt)
+	}
+	assert.Equal(t, cookieOptions{
+		domain:   "example.com",
+		path:     "/app",
+		url:      "https://example.com/",
+		secure:   true,
+		httpOnly: true,
+		sameSite: network.CookieSameSiteLax,
+		maxAge:   time.Hour,
+	}, opts)
+	assert.NoError(t, opts.validate())
+
+	ok, _ := opts.parse("example.com")
+	assert.False(t, ok)
+	ok, _ = opts.parse("color=red")
+	assert.False(t, ok)
+
+	for _, opt := range []string{"same_site=lax", "expires=tomorrow", "max_age=forever"} {
+		ok, err := opts.parse(opt)
+		assert.True(t, ok, opt)
+		assert.Error(t, err, opt)
+	}
+
+	ok, err := opts.parse("expires=2030-01-02T15:04:05Z")
+	assert.True(t, ok)
+	assert.NoError(t, err)
+	assert.Error(t, opts.validate())
+}
+
+func TestCookieOptions_parseScope(t *testing.T) {
+	var opts cookieOptions
+	assert.NoError(t, opts.parseScope("domain=example.com"))
+	assert.NoError(t, opts.parseScope("path=/"))
+	assert.Equal(t, cookieOptions{domain: "example.com", path: "/"}, opts)
+
+	assert.Error(t, opts.parseScope("secure"))
+	assert.Error(t, opts.parseScope("max_age=1h"))
+}
+
+func TestActionBuilderCookies(t *testing.T) {
+	valid := [][]string{
+		{"cookie", "key", "value"},
+		{"cookie", "key", "value", "example.com"},
+		{"cookie", "key", "value", "domain=example.com", "secure", "session"},
+		{"get_cookies", "cookies"},
+		{"get_cookies", "cookies", "https://example.com/", "https://example.org/"},
+		{"delete_cookie", "key"},
+		{"delete_cookie", "key", "domain=example.com", "path=/"},
+		{"clear_cookies"},
+	}
+	for _, args := range valid {
+		_, err := actionBuilder(testArgs(args...))
+		assert.NoError(t, err, args)
+	}
+
+	invalid := [][]string{
+		{"cookie", "key"},
+		{"cookie", "key", "value", "example.com", "example.org"},
+		{"cookie", "key", "value", "session", "max_age=1h"},
+		{"cookie", "key", "value", "same_site=Any"},
+		{"get_cookies"},
+		{"delete_cookie"},
+		{"delete_cookie", "key", "secure"},
+		{"clear_cookies", "key"},
+	}
+	for _, args := range invalid {
+		_, err := actionBuilder(testArgs(args...))
+		assert.Error(t, err, args)
+	}
+}
@@ -6,23 +6,25 @@ import (
 	"github.com/chromedp/chromedp"
 )
 
-type ctxCreatorFunc func(ctx context.Context) (context.Context, context.CancelFunc)
+// allocatorFunc creates allocator context which is used to launch or to connect to the browser.
+type allocatorFunc func(ctx context.Context) (context.Context, context.CancelFunc)
 
 var (
-	_ ctxCreatorFunc = chromedpCtxWithLocalChrome()
-	_ ctxCreatorFunc = chromedpCtxWithRemoteChrome("")
+	_ allocatorFunc = localChromeAllocator()
+	_ allocatorFunc = remoteChromeAllocator("")
 )
 
-func chromedpCtxWithLocalChrome() ctxCreatorFunc {
+// localChromeAllocator launches local Chrome with default options overridden by opts.
+func localChromeAllocator(opts ...chromedp.ExecAllocatorOption) allocatorFunc {
+	allocatorOpts := append(chromedp.DefaultExecAllocatorOptions[:], chromedp.Env("POWEREDBY=eliastor"))
+	allocatorOpts = append(allocatorOpts, opts...)
 	return func(parentCtx context.Context) (context.Context, context.CancelFunc) {
-		ctx, _ := chromedp.NewExecAllocator(parentCtx, append(chromedp.DefaultExecAllocatorOptions[:], chromedp.Env("POWEREDBY=eliastor"))...)
-		return chromedp.NewContext(ctx)
+		return chromedp.NewExecAllocator(parentCtx, allocatorOpts...)
 	}
 }
 
-func chromedpCtxWithRemoteChrome(remote string) ctxCreatorFunc {
+func remoteChromeAllocator(remote string) allocatorFunc {
 	return func(parentCtx context.Context) (context.Context, context.CancelFunc) {
-		ctx, _ := chromedp.NewRemoteAllocator(parentCtx, remote, chromedp.NoModifyURL)
-		return chromedp.NewContext(ctx)
+		return chromedp.NewRemoteAllocator(parentCtx, remote, chromedp.NoModifyURL)
 	}
 }
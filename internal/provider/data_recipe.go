@@ -2,22 +2,32 @@ package provider
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"fmt"
 	"os"
-	"path/filepath"
 
-	"github.com/chromedp/chromedp"
+	"github.com/chromedp/cdproto/page"
+	"github.com/hashicorp/terraform-plugin-framework-validators/boolvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/float64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
-var _ datasource.DataSource = &RecipeDataSource{}
+var (
+	_ datasource.DataSource                   = &RecipeDataSource{}
+	_ datasource.DataSourceWithValidateConfig = &RecipeDataSource{}
+)
 
 func NewRecipeDataSource() datasource.DataSource {
 	return &RecipeDataSource{}
@@ -30,11 +40,91 @@ type RecipeDataSource struct {
 // type Action []types.String
 
 type RecipeDataSourceModel struct {
-	Actions            [][]types.String `tfsdk:"actions"`
-	Values             types.Map        `tfsdk:"values"`
-	Id                 types.String     `tfsdk:"id"`
-	ScreenshotFilename types.String     `tfsdk:"screenshot_filename"`
-	ScreenshotSelector types.String     `tfsdk:"screenshot_selector"`
+	Actions             [][]types.String `tfsdk:"actions"`
+	Steps               []StepModel      `tfsdk:"steps"`
+	Values              types.Map        `tfsdk:"values"`
+	JSONValues          types.Map        `tfsdk:"json_values"`
+	SensitiveValues     types.Map        `tfsdk:"sensitive_values"`
+	Id                  types.String     `tfsdk:"id"`
+	ScreenshotFilename  types.String     `tfsdk:"screenshot_filename"`
+	Screenshot          types.Bool       `tfsdk:"screenshot"`
+	ScreenshotBase64    types.String     `tfsdk:"screenshot_base64"`
+	ScreenshotSHA256    types.String     `tfsdk:"screenshot_sha256"`
+	ScreenshotSelector  types.String     `tfsdk:"screenshot_selector"`
+	ScreenshotFullPage  types.Bool       `tfsdk:"screenshot_full_page"`
+	ScreenshotFormat    types.String     `tfsdk:"screenshot_format"`
+	ScreenshotQuality   types.Int64      `tfsdk:"screenshot_quality"`
+	ScreenshotClip      *ClipModel       `tfsdk:"screenshot_clip"`
+	ScreenshotScale     types.Float64    `tfsdk:"screenshot_scale"`
+	Session             types.String     `tfsdk:"session"`
+	FailureArtifactsDir types.String     `tfsdk:"failure_artifacts_dir"`
+	Timeout             types.String     `tfsdk:"timeout"`
+	Retry               *RetryModel      `tfsdk:"retry"`
+}
+
+// ClipModel is the area of the page in CSS pixels.
+type ClipModel struct {
+	X      types.Float64 `tfsdk:"x"`
+	Y      types.Float64 `tfsdk:"y"`
+	Width  types.Float64 `tfsdk:"width"`
+	Height types.Float64 `tfsdk:"height"`
+}
+
+// screenshotOptions returns options of the screenshot made after all actions.
+func (m RecipeDataSourceModel) screenshotOptions() (screenshotOptions, error) {
+	opts := screenshotOptions{
+		selector: m.ScreenshotSelector.ValueString(),
+		fullPage: m.ScreenshotFullPage.ValueBool(),
+		quality:  m.ScreenshotQuality.ValueInt64(),
+		scale:    m.ScreenshotScale.ValueFloat64(),
+	}
+	if format := m.ScreenshotFormat.ValueString(); format != "" {
+		var err error
+		opts.format, err = parseScreenshotFormat(format)
+		if err != nil {
+			return screenshotOptions{}, err
+		}
+	}
+	if m.ScreenshotClip != nil {
+		opts.clip = &page.Viewport{
+			X:      m.ScreenshotClip.X.ValueFloat64(),
+			Y:      m.ScreenshotClip.Y.ValueFloat64(),
+			Width:  m.ScreenshotClip.Width.ValueFloat64(),
+			Height: m.ScreenshotClip.Height.ValueFloat64(),
+		}
+	}
+	return opts, nil
+}
+
+// actionDefinitions returns all actions of the recipe regardless of the form they were defined in.
+func (m RecipeDataSourceModel) actionDefinitions() ([]actionDefinition, error) {
+	return actionDefinitions(m.Actions, m.Steps)
+}
+
+// actionDefinitions returns actions defined either in "actions" or in "steps" attribute.
+func actionDefinitions(actions [][]types.String, steps []StepModel) ([]actionDefinition, error) {
+	if steps == nil {
+		return actionListDefinitions(actions, path.Root("actions")), nil
+	}
+
+	defs := make([]actionDefinition, 0, len(steps))
+	for i, step := range steps {
+		args, err := step.actionArgs()
+		if err != nil {
+			return nil, fmt.Errorf("steps[%d]: %w", i, err)
+		}
+		defs = append(defs, actionDefinition{args: args, path: path.Root("steps").AtListIndex(i), columns: step.tableColumns()})
+	}
+	return defs, nil
+}
+
+// actionListDefinitions returns actions defined as lists of strings in the attribute at actionsPath.
+func actionListDefinitions(actions [][]types.String, actionsPath path.Path) []actionDefinition {
+	defs := make([]actionDefinition, 0, len(actions))
+	for i, args := range actions {
+		defs = append(defs, actionDefinition{args: args, path: actionsPath.AtListIndex(i), positional: true})
+	}
+	return defs
 }
 
 func (d *RecipeDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
@@ -46,7 +136,7 @@ func (d *RecipeDataSource) Schema(ctx context.Context, req datasource.SchemaRequ
 		MarkdownDescription: `Recipe runs list of action sequentially.
 If "screenshot_filename" is set it makes the screenshot after all actions executed.
 
-All actions are list of string where the first string is action name.
+Actions can be defined either in **actions** as list of strings where the first string is action name, or in **steps** as typed objects.
 
 In most cases the second string is selector for the action.
 
@@ -56,13 +146,162 @@ Open Devtools -> select element in DOM (or right click on the element at page an
 
 For more information about selectors https://en.wikipedia.org/wiki/CSS#Selector`,
 		Attributes: map[string]schema.Attribute{
-			"actions": schema.ListAttribute{
-				ElementType: types.ListType{
-					ElemType: types.StringType,
+			"actions": actionsAttribute(),
+			"steps":   stepsAttribute(),
+			"session": schema.StringAttribute{
+				Optional:            true,
+				Sensitive:           true,
+				Validators:          []validator.String{sessionValidator{}},
+				MarkdownDescription: "Session exported by **chromedp_session** data source. Cookies and storage of the session are restored before the actions run, so the recipe can skip the login.",
+			},
+
+			"id": schema.StringAttribute{
+				MarkdownDescription: "id of recipe",
+				Computed:            true,
+			},
+			"values":                valuesAttribute(caughtValuesDescription),
+			"json_values":           jsonValuesAttribute(),
+			"sensitive_values":      sensitiveValuesAttribute(),
+			"retry":                 retryAttribute(recipeRetryDescription),
+			"timeout":               timeoutAttribute(recipeTimeoutDescription),
+			"failure_artifacts_dir": failureArtifactsDirAttribute(),
+			"screenshot_filename": schema.StringAttribute{
+				Optional:    true,
+				Description: "If set screenshot at the end of the recipe will be made",
+			},
+			"screenshot": schema.BoolAttribute{
+				Optional:            true,
+				MarkdownDescription: "Makes the screenshot at the end of the recipe without saving it into the file. Use **screenshot_base64** to get it.",
+			},
+			"screenshot_base64": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Base64-encoded image of the screenshot made at the end of the recipe. Null if no screenshot is made.",
+			},
+			"screenshot_sha256": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Hex-encoded SHA-256 hash of the screenshot image made at the end of the recipe. Null if no screenshot is made.",
+			},
+			"screenshot_selector": schema.StringAttribute{
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(
+						path.MatchRelative().AtParent().AtName("screenshot_full_page"),
+						path.MatchRelative().AtParent().AtName("screenshot_clip"),
+					),
+				},
+				Description: "Requires **screenshot_filename** or **screenshot** to be set. Points frame to the selector before making the screenshot",
+			},
+			"screenshot_full_page": schema.BoolAttribute{
+				Optional: true,
+				Validators: []validator.Bool{
+					boolvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("screenshot_clip")),
+				},
+				MarkdownDescription: "Requires **screenshot_filename** or **screenshot** to be set. Captures the whole page instead of the visible part only.",
+			},
+			"screenshot_format": schema.StringAttribute{
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("png", "jpeg", "webp"),
+				},
+				MarkdownDescription: `Requires **screenshot_filename** or **screenshot** to be set. Image format of the screenshot: "png", "jpeg" or "webp". Defaults to "png".`,
+			},
+			"screenshot_quality": schema.Int64Attribute{
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AlsoRequires(path.MatchRelative().AtParent().AtName("screenshot_format")),
+					int64validator.Between(1, 100),
 				},
-				Required: true,
-				MarkdownDescription: `
-List of Actions. Each action is a list of arguments (strings).
+				MarkdownDescription: "Compression quality of the screenshot from 1 to 100. Requires **screenshot_format** to be \"jpeg\" or \"webp\".",
+			},
+			"screenshot_clip": schema.SingleNestedAttribute{
+				Optional:            true,
+				MarkdownDescription: "Requires **screenshot_filename** or **screenshot** to be set. Captures the area of the page in CSS pixels instead of the visible part.",
+				Attributes: map[string]schema.Attribute{
+					"x": schema.Float64Attribute{
+						Required:            true,
+						MarkdownDescription: "X offset of the area from the left of the page.",
+					},
+					"y": schema.Float64Attribute{
+						Required:            true,
+						MarkdownDescription: "Y offset of the area from the top of the page.",
+					},
+					"width": schema.Float64Attribute{
+						Required:            true,
+						MarkdownDescription: "Width of the area.",
+						Validators:          []validator.Float64{float64validator.AtLeast(1)},
+					},
+					"height": schema.Float64Attribute{
+						Required:            true,
+						MarkdownDescription: "Height of the area.",
+						Validators:          []validator.Float64{float64validator.AtLeast(1)},
+					},
+				},
+			},
+			"screenshot_scale": schema.Float64Attribute{
+				Optional: true,
+				Validators: []validator.Float64{
+					float64validator.AtLeast(0.01),
+				},
+				MarkdownDescription: "Requires **screenshot_filename** or **screenshot** to be set. Scale of the screenshot, e.g. 2 doubles its width and height. Defaults to 1.",
+			},
+		},
+	}
+}
+
+// recipeTimeoutDescription describes "timeout" attribute of recipes running all actions at once.
+const recipeTimeoutDescription = `Timeout of all actions, like "5m". Not limited by default.`
+
+// timeoutAttribute is the schema of "timeout" attribute limiting time of actions.
+func timeoutAttribute(description string) schema.StringAttribute {
+	return schema.StringAttribute{
+		Optional:            true,
+		MarkdownDescription: description,
+		Validators:          []validator.String{durationValidator{positive: true}},
+	}
+}
+
+// actionsAttribute is the schema of "actions" attribute shared by data sources running actions.
+func actionsAttribute() schema.ListAttribute {
+	return schema.ListAttribute{
+		ElementType: types.ListType{
+			ElemType: types.StringType,
+		},
+		Optional:   true,
+		Validators: []validator.List{listvalidator.ExactlyOneOf(path.MatchRoot("steps"))},
+		MarkdownDescription: `
+List of Actions. Each action is a list of arguments (strings). Conflicts with **steps**.
+Every action accepts the last argument "timeout=<duration>" which overrides **default_action_timeout** of the provider:
+
+	> ["wait_visible", "body footer", "timeout=10s"]
+
+Options are recognized only after all required arguments of the action, so required arguments are never taken for options,
+e.g. ["send_keys", "#q", "timeout=5"] types "timeout=5". The value name of **evaluate** is never taken for options either,
+so pass "" as the value name to set options of **evaluate** which doesn't catch the value: ["evaluate", "window.scrollTo(0, 0)", "", "timeout=5s"].
+
+Failed actions are retried by **retry** policy, which every action can override with the last arguments
+"retry=<attempts>", "retry_backoff=<duration>" and "retry_on=<classes>" (comma separated "timeout", "network", "javascript", "other"):
+
+	> ["click", "#submit", "retry=3", "retry_on=timeout,network"]
+
+Last argument "sensitive" places the value caught by the action into **sensitive_values** instead of **values** and hides arguments of the action in logs and errors.
+Values typed by **set_value** and **send_keys** and set by **cookie** and **storage_set** are always hidden in logs and errors:
+
+	> ["evaluate", "document.querySelector('#api-token').textContent", "api_token", "sensitive"]
+
+Arguments can refer to values caught by previous actions with "${values.<name>}" placeholders, which are replaced right before the action runs.
+Values are placed as into **values** attribute. Use "$${values.<name>}" in Terraform configuration, so Terraform doesn't interpolate them itself:
+
+	> ["text", "#item-id", "item_id"], ["navigate", "https://example.com/items/$${values.item_id}/edit"]
+
+Actions **if_present**, **if_visible** and **for_each_node** open blocks of nested actions, closed by ["end"] action. Blocks can be nested.
+Their selectors are CSS selectors matching elements only, unlike other actions they don't match text, XPath or attribute values.
+Nested actions of **for_each_node** run for every matching node. Their arguments refer to the current node with "${node}" placeholder,
+a selector matching only this node, and to its index with "${node.index}" placeholder:
+
+	> ["if_visible", "#accept-cookies"], ["click", "#accept-cookies"], ["end"]
+
+	> ["for_each_node", "ul.releases > li"], ["text", "$${node} .version", "version_$${node.index}"], ["end"]
+
 Supported actions:
 	- **navigate**: navigates the current frame to specific URL.
 	
@@ -92,6 +331,14 @@ Supported actions:
 	
 		in values["description"] one can find retrieved value.
 	
+	- **focus**: focuses the first element node matching the selector.
+
+		> ["focus", "#example-After textarea"]
+
+	- **press_enter**: sends Enter key to the first element node matching the selector.
+
+		> ["press_enter", "#example-After textarea"]
+
 	- **wait_visible**: waits until selector matched element is visible:
 
 		> ["wait_visible", "body footer"]
@@ -100,33 +347,106 @@ Supported actions:
 
 		> ["sleep", "3s"]
 
-	- **cookie**: sets the cookie, with arguments: cookie name, value, and optional domain. 
-				
+	- **cookie**: sets the cookie, with arguments: cookie name, value, and options:
+	"domain=<domain>", "path=<path>", "url=<url>", "secure", "http_only", "same_site=<Strict|Lax|None>",
+	"expires=<RFC 3339 timestamp>", "max_age=<duration>" or "session" (no expiration date).
+	The cookie expires in 24 hours unless one of "expires", "max_age" and "session" is given.
+	Plain third argument is the domain, as in earlier versions.
+
 		> ["cookie", "key", "value", "example.com"]
 
-				`,
-			},
+		> ["cookie", "token", "secret", "domain=example.com", "path=/", "secure", "http_only", "same_site=Lax", "max_age=720h"]
 
-			"id": schema.StringAttribute{
-				MarkdownDescription: "id of recipe",
-				Computed:            true,
-			},
-			"values": schema.MapAttribute{
-				ElementType: types.StringType,
-				Computed:    true,
-				Description: `
-Map of output values from **value** and **text** actions.`,
-			},
-			"screenshot_filename": schema.StringAttribute{
-				Optional:    true,
-				Description: "If set screenshot at the end of the recipe will be made",
-			},
-			"screenshot_selector": schema.StringAttribute{
-				Optional:    true,
-				Validators:  []validator.String{stringvalidator.AlsoRequires(path.MatchRelative().AtParent().AtName("screenshot_filename"))},
-				Description: "Requires **screenshot_filename** to be set. Points frame to the selector before making the screenshot",
-			},
-		},
+	- **get_cookies**: gets cookies of the current page, or of URLs given as further arguments, as a list of objects. The list is placed into "values" (JSON-encoded) and "json_values" attributes under the key given as the first argument.
+
+		> ["get_cookies", "cookies", "https://example.com/"]
+
+	- **delete_cookie**: deletes cookies with the name, optionally narrowed down with "domain=<domain>", "path=<path>" and "url=<url>" options.
+
+		> ["delete_cookie", "token", "domain=example.com"]
+
+	- **clear_cookies**: deletes all cookies.
+
+		> ["clear_cookies"]
+
+	- **text_all**, **value_all**: retrieves the text or the ".value" field of every element node matching the selector. Last argument places the list of caught values into "values" (JSON-encoded) and "json_values" attributes under specified key.
+
+		> ["text_all", "table.releases td.version", "versions"]
+
+	- **attribute_all**: gets the attribute of every element node matching the selector, nodes without the attribute give empty strings. Arguments are selector, attribute name and value name.
+
+		> ["attribute_all", "nav a", "href", "links"]
+
+	- **attribute**: gets the attribute of the first element node matching the selector, missing attribute gives empty string. Arguments are selector, attribute name and value name.
+
+		> ["attribute", "a.download", "href", "download_url"]
+
+	- **attributes**: gets all attributes of the first element node matching the selector as a map. Last argument places the map into "values" (JSON-encoded) and "json_values" attributes under specified key.
+
+		> ["attributes", "#app", "app_attributes"]
+
+	- **set_attribute**: sets the attribute of the first element node matching the selector. Arguments are selector, attribute name and value.
+
+		> ["set_attribute", "#submit", "data-confirmed", "true"]
+
+	- **remove_attribute**: removes the attribute from the first element node matching the selector.
+
+		> ["remove_attribute", "#submit", "disabled"]
+
+	- **table**: reads the first table matching the selector as a list of maps from column name to cell text. Arguments are selector, value name and options:
+	"header_row=<index>" sets the row with column names (rows above it are skipped), "column:<header text>=<new name>" renames the column.
+	Header cells without text are named "column_<index>".
+
+		> ["table", "table#users", "users", "header_row=1", "column:Full Name=name"]
+
+	- **screenshot**: makes the screenshot and saves it into the file, creating its directory. Arguments are file path, optional selector of the element to capture and options:
+	"full_page" captures the whole page, "format=<png|jpeg|webp>" sets the image format, "quality=<1-100>" sets compression quality and requires "format=jpeg" or "format=webp", "scale=<number>" scales the image.
+
+		> ["screenshot", "screenshots/login.png", "form#login"]
+
+		> ["screenshot", "screenshots/confirmation.jpeg", "full_page", "format=jpeg", "quality=80"]
+
+	- **storage_get**: gets the item of the current page's storage, missing item gives empty string. Arguments are storage ("local" for localStorage or "session" for sessionStorage), key and value name.
+
+		> ["storage_get", "local", "feature_flags", "flags"]
+
+	- **storage_set**: sets the item of the current page's storage. Arguments are storage, key and value.
+
+		> ["storage_set", "session", "token", "secret"]
+
+	- **storage_remove**: removes the item from the current page's storage. Arguments are storage and key.
+
+		> ["storage_remove", "local", "feature_flags"]
+
+	- **storage_clear**: removes all items from the current page's storage.
+
+		> ["storage_clear", "local"]
+
+	- **if_present**: runs nested actions up to ["end"] only if any node matches the selector. It doesn't wait for the node, so it's suited for elements which appear only sometimes, like cookie banners.
+
+		> ["if_present", "#cookie-banner"]
+
+	- **if_visible**: runs nested actions up to ["end"] only if any node matching the selector is visible. It doesn't wait for the node.
+
+		> ["if_visible", "#cookie-banner button.accept"]
+
+	- **for_each_node**: runs nested actions up to ["end"] for every node matching the selector. Nodes are marked with "data-chromedp-node" attribute,
+	so nested actions shouldn't navigate away from the page.
+
+		> ["for_each_node", "table.users tbody tr"]
+
+	- **end**: closes the innermost **if_present**, **if_visible** or **for_each_node** action.
+
+		> ["end"]
+
+	- **evaluate**: evaluates JavaScript expression. Optional second argument places the result into "values" attribute under specified key.
+	Strings are placed as is, other results are JSON-encoded. Further arguments are options: "await" waits for the returned promise to be resolved, "json" JSON-encodes strings too.
+
+		> ["evaluate", "JSON.stringify(window.__APP_CONFIG__)", "config"]
+
+		> ["evaluate", "fetch('/api/version').then(r => r.text())", "version", "await"]
+
+		`,
 	}
 }
 
@@ -149,9 +469,71 @@ func (d *RecipeDataSource) Configure(ctx context.Context, req datasource.Configu
 	d.data = data
 }
 
-func (d *RecipeDataSource) run(ctx context.Context, actions []chromedp.Action) error {
-	err := chromedp.Run(ctx, actions...)
-	return err
+func (d *RecipeDataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+	var actions, steps types.List
+
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("actions"), &actions)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("steps"), &steps)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(validateActions(actions, path.Root("actions"))...)
+	resp.Diagnostics.Append(validateSteps(ctx, steps, path.Root("steps"))...)
+	resp.Diagnostics.Append(validateScreenshotOptions(ctx, req.Config)...)
+	resp.Diagnostics.Append(validateScreenshotQuality(ctx, req.Config)...)
+}
+
+// screenshotOptionNames are attributes configuring the screenshot made at the end of the recipe.
+var screenshotOptionNames = []string{"screenshot_selector", "screenshot_full_page", "screenshot_format", "screenshot_clip", "screenshot_scale"}
+
+// validateScreenshotOptions checks that screenshot options are set only together with the screenshot.
+func validateScreenshotOptions(ctx context.Context, config tfsdk.Config) diag.Diagnostics {
+	var diags diag.Diagnostics
+	var filename types.String
+	var enabled types.Bool
+
+	diags.Append(config.GetAttribute(ctx, path.Root("screenshot_filename"), &filename)...)
+	diags.Append(config.GetAttribute(ctx, path.Root("screenshot"), &enabled)...)
+	if diags.HasError() || !filename.IsNull() || enabled.IsUnknown() || enabled.ValueBool() {
+		return diags
+	}
+
+	for _, name := range screenshotOptionNames {
+		var value attr.Value
+		diags.Append(config.GetAttribute(ctx, path.Root(name), &value)...)
+		if value != nil && !value.IsNull() {
+			diags.AddAttributeError(
+				path.Root(name),
+				"Missing Attribute Configuration",
+				fmt.Sprintf("%s requires screenshot_filename or screenshot to be set.", name),
+			)
+		}
+	}
+	return diags
+}
+
+// validateScreenshotQuality checks that the quality is set only for formats with the compression quality.
+// Missing format is reported by the validator of the quality.
+func validateScreenshotQuality(ctx context.Context, config tfsdk.Config) diag.Diagnostics {
+	var diags diag.Diagnostics
+	var format types.String
+	var quality types.Int64
+
+	diags.Append(config.GetAttribute(ctx, path.Root("screenshot_format"), &format)...)
+	diags.Append(config.GetAttribute(ctx, path.Root("screenshot_quality"), &quality)...)
+	if diags.HasError() || quality.IsNull() || format.IsNull() || format.IsUnknown() {
+		return diags
+	}
+
+	if !hasQuality(page.CaptureScreenshotFormat(format.ValueString())) {
+		diags.AddAttributeError(
+			path.Root("screenshot_quality"),
+			"Invalid Attribute Combination",
+			fmt.Sprintf(`screenshot_quality applies only to "jpeg" and "webp" screenshot_format, got %q.`, format.ValueString()),
+		)
+	}
+	return diags
 }
 
 func (d *RecipeDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
@@ -163,59 +545,77 @@ func (d *RecipeDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 	}
 	data.Id = types.StringValue("placeholder")
 
-	values := map[string]*string{}
+	values := map[string]interface{}{}
 
-	var actions []chromedp.Action
+	defs, err := data.actionDefinitions()
+	if err != nil {
+		resp.Diagnostics.AddError("wrong action definition", err.Error())
+		return
+	}
 
-	tflog.Debug(ctx, "loop over actions")
-	for _, actionArgs := range data.Actions {
-		tflog.Debug(ctx, "building actions", map[string]interface{}{"args": actionArgs})
-		action, err := actionBuilder(actionArgs)
-		if err != nil {
-			resp.Diagnostics.AddError("wrong action definition", err.Error())
-			continue
-		}
-		actions = append(actions, action.Action(values))
+	steps, diags := buildActions(ctx, defs, values)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
+	sessionSteps, diags := restoreSessionSteps(data.Session)
+	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	steps = append(sessionSteps, steps...)
 
 	picbuf := []byte{}
 	screenshotPath := data.ScreenshotFilename.ValueString()
-	screenshotRequested := screenshotPath != ""
+	screenshotRequested := screenshotPath != "" || data.Screenshot.ValueBool()
 	if screenshotRequested {
-		screenshotDir := filepath.Dir(screenshotPath)
-		err := os.MkdirAll(screenshotDir, 0600)
-		if err != nil {
-			resp.Diagnostics.AddError("can't create directory for the screenshot", fmt.Sprintf("%s: %v", screenshotDir, err))
-			return
+		if screenshotPath != "" {
+			err := createScreenshotDir(screenshotPath)
+			if err != nil {
+				resp.Diagnostics.AddError("can't create directory for the screenshot", err.Error())
+				return
+			}
 		}
 
-		selector := data.ScreenshotSelector.ValueString()
-		if selector != "" {
-			actions = append(actions, chromedp.Screenshot(selector, &picbuf, chromedp.NodeVisible))
-		} else {
-			actions = append(actions, chromedp.CaptureScreenshot(&picbuf))
+		opts, err := data.screenshotOptions()
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("screenshot_format"), "wrong screenshot options", err.Error())
+			return
 		}
+		steps = append(steps, recipeStep{action: screenshot(opts, &picbuf), name: "screenshot of the recipe"})
+	}
+	runOpts, diags := d.data.runOptions(data.Timeout, data.FailureArtifactsDir, data.Retry)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
-	dpCtx, cancel := d.data.ctxCreator(ctx)
-	defer cancel()
 
-	err := d.run(dpCtx, actions)
-	if err != nil {
-		resp.Diagnostics.AddError("can't process actions", err.Error())
+	dpCtx, release, diags := d.data.newTab(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
-	var diags diag.Diagnostics
+	defer release()
 
-	data.Values, diags = types.MapValueFrom(ctx, types.StringType, values)
+	resp.Diagnostics.Append(runSteps(ctx, dpCtx, steps, runOpts)...)
+	data.Values, data.JSONValues, diags = outputMaps(ctx, values)
+	resp.Diagnostics.Append(diags...)
+	data.SensitiveValues, diags = sensitiveOutputMap(ctx, values)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	data.ScreenshotBase64 = types.StringNull()
+	data.ScreenshotSHA256 = types.StringNull()
 	if screenshotRequested {
+		hash := sha256.Sum256(picbuf)
+		data.ScreenshotBase64 = types.StringValue(base64.StdEncoding.EncodeToString(picbuf))
+		data.ScreenshotSHA256 = types.StringValue(hex.EncodeToString(hash[:]))
+	}
+
+	if screenshotPath != "" {
 		err = os.WriteFile(screenshotPath, picbuf, 0600)
 		if err != nil {
 			resp.Diagnostics.AddError("can't save the screenshot:", err.Error())
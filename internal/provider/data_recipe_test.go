@@ -1,6 +1,7 @@
 package provider
 
 import (
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
@@ -22,6 +23,27 @@ func TestAccRecipeDataSource(t *testing.T) {
 					resource.TestCheckResourceAttr("data.chromedp_recipe.test", "actions.0.0", "navigate"),
 					resource.TestCheckResourceAttr("data.chromedp_recipe.test", "values.text", "package main\n\nimport (\n\t\"fmt\"\n\t\"time\"\n)\n\nvar c chan int\n\nfunc handle(int) {}\n\nfunc main() {\n\tselect {\n\tcase m := <-c:\n\t\thandle(m)\n\tcase <-time.After(10 * time.Second):\n\t\tfmt.Println(\"timed out\")\n\t}\n}\n"),
 					resource.TestCheckResourceAttr("data.chromedp_recipe.test", "values.runtext2", "hehe\n"),
+					resource.TestCheckResourceAttr("data.chromedp_recipe.test", "json_values.runtext2", `"hehe\n"`),
+					resource.TestCheckResourceAttrSet("data.chromedp_recipe.test", "screenshot_base64"),
+					resource.TestCheckResourceAttrSet("data.chromedp_recipe.test", "screenshot_sha256"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccRecipeDataSourceSteps(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testPreCheck(t)
+		},
+		ProtoV6ProviderFactories: testProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + testRecipeDataSourceStepsConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.chromedp_recipe.test", "steps.0.navigate.url", "https://pkg.go.dev/time"),
+					resource.TestCheckResourceAttrSet("data.chromedp_recipe.test", "values.description"),
 				),
 			},
 		},
@@ -63,5 +85,274 @@ data "chromedp_recipe" "test" {
   }
 `
 
+func TestAccRecipeDataSource_interpolation(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testPreCheck(t)
+		},
+		ProtoV6ProviderFactories: testProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+data "chromedp_recipe" "test" {
+	actions = [
+	  ["evaluate", "'time'", "package"],
+	  ["navigate", "https://pkg.go.dev/$${values.package}"],
+	  ["evaluate", "location.pathname", "path"],
+	]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.chromedp_recipe.test", "values.path", "/time"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccRecipeDataSource_unknownPlaceholder(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+data "chromedp_recipe" "test" {
+	actions = [
+	  ["navigate", "https://pkg.go.dev/$${values.package}"],
+	  ["evaluate", "'time'", "package"],
+	]
+}
+`,
+				ExpectError: regexp.MustCompile(`value "package" is not caught by previous actions`),
+			},
+		},
+	})
+}
+
+func TestAccRecipeDataSource_controlFlow(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testPreCheck(t)
+		},
+		ProtoV6ProviderFactories: testProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+data "chromedp_recipe" "test" {
+	actions = [
+	  ["navigate", "data:text/html,<ul><li><b>v1</b></li><li><b>v2</b></li></ul><button id='accept' style='display:none'>OK</button><a class='slider' href='%23cookie-banner'>li %23cookie-banner</a>"],
+	  ["if_present", "#cookie-banner"],
+	  ["evaluate", "'clicked'", "banner"],
+	  ["end"],
+	  ["if_visible", "#accept"],
+	  ["evaluate", "'clicked'", "accept"],
+	  ["end"],
+	  ["if_present", "#accept"],
+	  ["evaluate", "'present'", "accept_present"],
+	  ["end"],
+	  ["for_each_node", "li"],
+	  ["text", "$${node} b", "version_$${node.index}"],
+	  ["end"],
+	]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("data.chromedp_recipe.test", "values.banner"),
+					resource.TestCheckNoResourceAttr("data.chromedp_recipe.test", "values.accept"),
+					resource.TestCheckResourceAttr("data.chromedp_recipe.test", "values.accept_present", "present"),
+					resource.TestCheckResourceAttr("data.chromedp_recipe.test", "values.version_0", "v1"),
+					resource.TestCheckResourceAttr("data.chromedp_recipe.test", "values.version_1", "v2"),
+					// Texts and attribute values containing selectors don't match.
+					resource.TestCheckNoResourceAttr("data.chromedp_recipe.test", "values.version_2"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccRecipeDataSource_unclosedBlock(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+data "chromedp_recipe" "test" {
+	actions = [
+	  ["if_present", "#cookie-banner"],
+	  ["click", "#cookie-banner button"],
+	]
+}
+`,
+				ExpectError: regexp.MustCompile(`if_present action isn't closed by \["end"\] action`),
+			},
+		},
+	})
+}
+
+func TestAccRecipeDataSource_noMatches(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testPreCheck(t)
+		},
+		ProtoV6ProviderFactories: testProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// Timeouts fail the test instead of waiting forever if the actions wait for nodes.
+				Config: providerConfig + `
+data "chromedp_recipe" "test" {
+	actions = [
+	  ["navigate", "data:text/html,<p>no lists here</p>"],
+	  ["text_all", "li", "texts", "timeout=10s"],
+	  ["value_all", "input", "inputs", "timeout=10s"],
+	  ["attribute_all", "a", "href", "links", "timeout=10s"],
+	]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.chromedp_recipe.test", "values.texts", "[]"),
+					resource.TestCheckResourceAttr("data.chromedp_recipe.test", "values.inputs", "[]"),
+					resource.TestCheckResourceAttr("data.chromedp_recipe.test", "values.links", "[]"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccRecipeDataSource_connectionError(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testPreCheck(t)
+		},
+		ProtoV6ProviderFactories: testProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "chromedp" {
+	endpoint        = "ws://127.0.0.1:1"
+	connect_timeout = "5s"
+}
+
+data "chromedp_recipe" "test" {
+	actions = [
+	  ["navigate", "about:blank"],
+	]
+}
+`,
+				ExpectError: regexp.MustCompile("Can't connect to the browser"),
+			},
+		},
+	})
+}
+
+func TestAccRecipeDataSource_invalidSession(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testPreCheck(t)
+		},
+		ProtoV6ProviderFactories: testProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+data "chromedp_recipe" "test" {
+	session = "cookies"
+	actions = [
+	  ["navigate", "about:blank"],
+	]
+}
+`,
+				ExpectError: regexp.MustCompile("Invalid Session"),
+			},
+		},
+	})
+}
+
+func TestAccRecipeDataSource_screenshotConflicts(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testPreCheck(t)
+		},
+		ProtoV6ProviderFactories: testProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+data "chromedp_recipe" "test" {
+	screenshot_filename  = "test.png"
+	screenshot_full_page = true
+	screenshot_clip      = { x = 0, y = 0, width = 100, height = 100 }
+	actions = [
+	  ["navigate", "about:blank"],
+	]
+}
+`,
+				ExpectError: regexp.MustCompile("Invalid Attribute Combination"),
+			},
+			{
+				Config: providerConfig + `
+data "chromedp_recipe" "test" {
+	screenshot_full_page = true
+	actions = [
+	  ["navigate", "about:blank"],
+	]
+}
+`,
+				ExpectError: regexp.MustCompile("screenshot_full_page requires screenshot_filename or screenshot"),
+			},
+			{
+				Config: providerConfig + `
+data "chromedp_recipe" "test" {
+	screenshot_filename = "test.png"
+	screenshot_format   = "png"
+	screenshot_quality  = 80
+	actions = [
+	  ["navigate", "about:blank"],
+	]
+}
+`,
+				ExpectError: regexp.MustCompile(`screenshot_quality applies only to "jpeg" and "webp"`),
+			},
+		},
+	})
+}
+
+const testRecipeDataSourceStepsConfig = `
+data "chromedp_recipe" "test" {
+	steps = [
+	  { navigate = { url = "https://pkg.go.dev/time" } },
+	  { wait_visible = { selector = "body footer" } },
+	  { click = { selector = "#example-After", wait_visible = true } },
+	  { text = { selector = "div.Documentation-function:has(#After) p", name = "description" } },
+	]
+  }
+`
+
 //TODO: add cookie test
 //Remove dependency on internet in tests
+
+func TestAccRecipeDataSource_invalidSteps(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+data "chromedp_recipe" "test" {
+	steps = [
+	  { if_present = { selector = "#cookie-banner" } },
+	  { click = { selector = "#cookie-banner button" } },
+	]
+}
+`,
+				ExpectError: regexp.MustCompile(`if_present action isn't closed by \["end"\] action`),
+			},
+			{
+				Config: `
+data "chromedp_recipe" "test" {
+	steps = [
+	  { sleep = { duration = "3 seconds" } },
+	]
+}
+`,
+				ExpectError: regexp.MustCompile(`can't parse duration for sleep`),
+			},
+		},
+	})
+}
@@ -0,0 +1,168 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ datasource.DataSource                   = &SessionDataSource{}
+	_ datasource.DataSourceWithValidateConfig = &SessionDataSource{}
+)
+
+func NewSessionDataSource() datasource.DataSource {
+	return &SessionDataSource{}
+}
+
+// SessionDataSource runs actions, usually a login, and exports the browser session for other recipes.
+type SessionDataSource struct {
+	data *providerData
+}
+
+type SessionDataSourceModel struct {
+	Actions             [][]types.String `tfsdk:"actions"`
+	Steps               []StepModel      `tfsdk:"steps"`
+	Session             types.String     `tfsdk:"session"`
+	Values              types.Map        `tfsdk:"values"`
+	JSONValues          types.Map        `tfsdk:"json_values"`
+	SensitiveValues     types.Map        `tfsdk:"sensitive_values"`
+	Id                  types.String     `tfsdk:"id"`
+	FailureArtifactsDir types.String     `tfsdk:"failure_artifacts_dir"`
+	Timeout             types.String     `tfsdk:"timeout"`
+	Retry               *RetryModel      `tfsdk:"retry"`
+}
+
+func (d *SessionDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_session"
+}
+
+func (d *SessionDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: `Session runs list of actions, usually a login flow, and exports the browser session:
+cookies of the browser context, and localStorage and sessionStorage of the page open after the last action.
+
+The exported session is imported into **chromedp_recipe** with its **session** attribute, so the recipe starts logged in.
+
+Actions are the same as actions of **chromedp_recipe**.`,
+		Attributes: map[string]schema.Attribute{
+			"actions": actionsAttribute(),
+			"steps":   stepsAttribute(),
+
+			"retry":                 retryAttribute(recipeRetryDescription),
+			"timeout":               timeoutAttribute(recipeTimeoutDescription),
+			"failure_artifacts_dir": failureArtifactsDirAttribute(),
+			"session": schema.StringAttribute{
+				Computed:            true,
+				Sensitive:           true,
+				MarkdownDescription: "JSON-encoded session with `cookies` list and `origins` list of objects with `origin`, `local_storage` and `session_storage` attributes.",
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "id of session",
+				Computed:            true,
+			},
+			"values":           valuesAttribute(caughtValuesDescription),
+			"json_values":      jsonValuesAttribute(),
+			"sensitive_values": sensitiveValuesAttribute(),
+		},
+	}
+}
+
+func (d *SessionDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*providerData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *providerData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.data = data
+}
+
+func (d *SessionDataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+	var actions, steps types.List
+
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("actions"), &actions)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("steps"), &steps)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(validateActions(actions, path.Root("actions"))...)
+	resp.Diagnostics.Append(validateSteps(ctx, steps, path.Root("steps"))...)
+}
+
+func (d *SessionDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data SessionDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.Id = types.StringValue("placeholder")
+
+	values := map[string]interface{}{}
+
+	defs, err := actionDefinitions(data.Actions, data.Steps)
+	if err != nil {
+		resp.Diagnostics.AddError("wrong action definition", err.Error())
+		return
+	}
+
+	steps, diags := buildActions(ctx, defs, values)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var state sessionState
+	steps = append(steps, recipeStep{action: captureSession(&state), name: "capturing session"})
+
+	runOpts, diags := d.data.runOptions(data.Timeout, data.FailureArtifactsDir, data.Retry)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	dpCtx, release, diags := d.data.newTab(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer release()
+
+	resp.Diagnostics.Append(runSteps(ctx, dpCtx, steps, runOpts)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	session, err := json.Marshal(state)
+	if err != nil {
+		resp.Diagnostics.AddError("can't encode the session", err.Error())
+		return
+	}
+	data.Session = types.StringValue(string(session))
+
+	data.Values, data.JSONValues, diags = outputMaps(ctx, values)
+	resp.Diagnostics.Append(diags...)
+	data.SensitiveValues, diags = sensitiveOutputMap(ctx, values)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
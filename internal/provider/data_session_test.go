@@ -0,0 +1,45 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccSessionDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testPreCheck(t)
+		},
+		ProtoV6ProviderFactories: testProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + testSessionDataSourceConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.chromedp_session.test", "session"),
+					resource.TestCheckResourceAttr("data.chromedp_recipe.test", "values.token", "secret"),
+					resource.TestCheckResourceAttr("data.chromedp_recipe.test", "values.theme", "dark"),
+				),
+			},
+		},
+	})
+}
+
+const testSessionDataSourceConfig = `
+data "chromedp_session" "test" {
+	actions = [
+	  ["navigate", "https://pkg.go.dev/time"],
+	  ["cookie", "token", "secret", "domain=pkg.go.dev"],
+	  ["evaluate", "localStorage.setItem('theme', 'dark')"],
+	]
+}
+
+data "chromedp_recipe" "test" {
+	session = data.chromedp_session.test.session
+	actions = [
+	  ["navigate", "https://pkg.go.dev/time"],
+	  ["evaluate", "document.cookie.split('; ').find(c => c.startsWith('token=')).split('=')[1]", "token"],
+	  ["evaluate", "localStorage.getItem('theme')", "theme"],
+	]
+}
+`
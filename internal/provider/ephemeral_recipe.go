@@ -0,0 +1,163 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ ephemeral.EphemeralResource                   = &RecipeEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithConfigure      = &RecipeEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithValidateConfig = &RecipeEphemeralResource{}
+)
+
+func NewRecipeEphemeralResource() ephemeral.EphemeralResource {
+	return &RecipeEphemeralResource{}
+}
+
+// RecipeEphemeralResource runs actions like chromedp_recipe data source, but its values are never saved in the plan or state.
+type RecipeEphemeralResource struct {
+	data *providerData
+}
+
+type RecipeEphemeralResourceModel struct {
+	Actions             [][]types.String `tfsdk:"actions"`
+	Steps               []StepModel      `tfsdk:"steps"`
+	Values              types.Map        `tfsdk:"values"`
+	JSONValues          types.Map        `tfsdk:"json_values"`
+	SensitiveValues     types.Map        `tfsdk:"sensitive_values"`
+	Session             types.String     `tfsdk:"session"`
+	FailureArtifactsDir types.String     `tfsdk:"failure_artifacts_dir"`
+	Timeout             types.String     `tfsdk:"timeout"`
+	Retry               *RetryModel      `tfsdk:"retry"`
+}
+
+func (r *RecipeEphemeralResource) Metadata(ctx context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_recipe"
+}
+
+func (r *RecipeEphemeralResource) Schema(ctx context.Context, req ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	// Ephemeral recipes usually catch secrets, so all their values are sensitive.
+	values, jsonValues := valuesAttribute(caughtValuesDescription), jsonValuesAttribute()
+	values.Sensitive, jsonValues.Sensitive = true, true
+
+	resp.Schema = schema.Schema{
+		MarkdownDescription: `Ephemeral recipe runs list of actions like **chromedp_recipe** data source, but its values exist only during the Terraform run
+and are never saved in the plan or state. Use it to scrape API tokens and other secrets from web consoles
+and pass them into configuration of other providers. Requires Terraform 1.10 or later.
+
+Actions and steps are the same as actions and steps of **chromedp_recipe** data source.`,
+		Attributes: map[string]schema.Attribute{
+			"actions": ephemeralAttribute(actionsAttribute()),
+			"steps":   ephemeralAttribute(stepsAttribute()),
+			"session": schema.StringAttribute{
+				Optional:            true,
+				Sensitive:           true,
+				MarkdownDescription: "Browser session exported by **chromedp_session** data source. It's restored before the first action.",
+				Validators:          []validator.String{sessionValidator{}},
+			},
+			"retry":                 ephemeralAttribute(retryAttribute(recipeRetryDescription)),
+			"timeout":               ephemeralAttribute(timeoutAttribute(recipeTimeoutDescription)),
+			"failure_artifacts_dir": ephemeralAttribute(failureArtifactsDirAttribute()),
+			"values":                ephemeralAttribute(values),
+			"json_values":           ephemeralAttribute(jsonValues),
+			"sensitive_values":      ephemeralAttribute(sensitiveValuesAttribute()),
+		},
+	}
+}
+
+func (r *RecipeEphemeralResource) Configure(ctx context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*providerData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Ephemeral Resource Configure Type",
+			fmt.Sprintf("Expected *providerData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.data = data
+}
+
+func (r *RecipeEphemeralResource) ValidateConfig(ctx context.Context, req ephemeral.ValidateConfigRequest, resp *ephemeral.ValidateConfigResponse) {
+	var actions, steps types.List
+
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("actions"), &actions)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("steps"), &steps)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(validateActions(actions, path.Root("actions"))...)
+	resp.Diagnostics.Append(validateSteps(ctx, steps, path.Root("steps"))...)
+}
+
+func (r *RecipeEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data RecipeEphemeralResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	values := map[string]interface{}{}
+
+	steps, diags := restoreSessionSteps(data.Session)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	defs, err := actionDefinitions(data.Actions, data.Steps)
+	if err != nil {
+		resp.Diagnostics.AddError("wrong action definition", err.Error())
+		return
+	}
+
+	actionSteps, diags := buildActions(ctx, defs, values)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	steps = append(steps, actionSteps...)
+
+	runOpts, diags := r.data.runOptions(data.Timeout, data.FailureArtifactsDir, data.Retry)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tabCtx, release, diags := r.data.newTab(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer release()
+
+	resp.Diagnostics.Append(runSteps(ctx, tabCtx, steps, runOpts)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Values, data.JSONValues, diags = outputMaps(ctx, values)
+	resp.Diagnostics.Append(diags...)
+	data.SensitiveValues, diags = sensitiveOutputMap(ctx, values)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+}
@@ -0,0 +1,104 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/chromedp/chromedp"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+)
+
+// failureArtifactsDirAttribute is the schema of "failure_artifacts_dir" attribute.
+func failureArtifactsDirAttribute() schema.StringAttribute {
+	return schema.StringAttribute{
+		Optional:            true,
+		MarkdownDescription: "If set and an action fails, full page screenshot, HTML and URL of the page are saved into new subdirectory of this directory. Paths of the files are shown in the error.",
+	}
+}
+
+// failureArtifactsTimeout limits time of capturing the page after the failed action.
+const failureArtifactsTimeout = 30 * time.Second
+
+// failureArtifact is the file describing the page at the moment of the failure.
+type failureArtifact struct {
+	// description is shown in the diagnostic next to the file path.
+	description string
+	filename    string
+	capture     func(ctx context.Context) ([]byte, error)
+}
+
+var failureArtifacts = []failureArtifact{
+	{
+		description: "full page screenshot",
+		filename:    "screenshot.png",
+		capture: func(ctx context.Context) ([]byte, error) {
+			var buf []byte
+			err := chromedp.Run(ctx, screenshot(screenshotOptions{fullPage: true}, &buf))
+			return buf, err
+		},
+	},
+	{
+		description: "page HTML",
+		filename:    "page.html",
+		capture: func(ctx context.Context) ([]byte, error) {
+			var html string
+			err := chromedp.Run(ctx, chromedp.Evaluate("document.documentElement ? document.documentElement.outerHTML : ''", &html))
+			return []byte(html), err
+		},
+	},
+	{
+		description: "page URL",
+		filename:    "url.txt",
+		capture: func(ctx context.Context) ([]byte, error) {
+			var url string
+			err := chromedp.Run(ctx, chromedp.Location(&url))
+			return []byte(url + "\n"), err
+		},
+	},
+}
+
+// saveFailureArtifacts captures the page of the tab into new directory under dir.
+// Artifacts are captured independently, so one failed capture doesn't prevent others.
+// It returns the report of saved files for the diagnostic.
+func saveFailureArtifacts(tabCtx context.Context, dir string) string {
+	if tabCtx.Err() != nil {
+		return fmt.Sprintf("Failure artifacts are not captured, the tab is closed: %v", tabCtx.Err())
+	}
+	ctx, cancel := context.WithTimeout(tabCtx, failureArtifactsTimeout)
+	defer cancel()
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Sprintf("Failure artifacts are not captured: %v", err)
+	}
+	failureDir, err := os.MkdirTemp(dir, "failure-"+time.Now().Format("20060102-150405")+"-")
+	if err != nil {
+		return fmt.Sprintf("Failure artifacts are not captured: %v", err)
+	}
+
+	lines := []string{"Failure artifacts:"}
+	for _, artifact := range failureArtifacts {
+		content, err := artifact.capture(ctx)
+		if err == nil {
+			filename := filepath.Join(failureDir, artifact.filename)
+			err = os.WriteFile(filename, content, 0600)
+			if err == nil {
+				lines = append(lines, fmt.Sprintf("  %s: %s", artifact.description, filename))
+				continue
+			}
+		}
+		lines = append(lines, fmt.Sprintf("  %s: can't capture: %v", artifact.description, err))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// actionsErrorDetail returns the diagnostic detail of failed actions, with failure artifacts if dir is set.
+func actionsErrorDetail(tabCtx context.Context, dir string, err error) string {
+	if dir == "" {
+		return err.Error()
+	}
+	return err.Error() + "\n\n" + saveFailureArtifacts(tabCtx, dir)
+}
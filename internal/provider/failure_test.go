@@ -0,0 +1,34 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActionsErrorDetail(t *testing.T) {
+	err := errors.New("could not find node")
+	assert.Equal(t, "could not find node", actionsErrorDetail(context.Background(), "", err))
+
+	dir := t.TempDir()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	detail := actionsErrorDetail(ctx, dir, err)
+	assert.Contains(t, detail, "could not find node\n\nFailure artifacts are not captured")
+
+	// Captures fail without the browser, but every artifact is reported.
+	detail = actionsErrorDetail(context.Background(), dir, err)
+	for _, artifact := range failureArtifacts {
+		assert.Contains(t, detail, artifact.description+": can't capture")
+	}
+	failures, _ := filepath.Glob(filepath.Join(dir, "failure-*"))
+	if assert.Len(t, failures, 1) {
+		info, err := os.Stat(failures[0])
+		assert.NoError(t, err)
+		assert.True(t, info.IsDir())
+	}
+}
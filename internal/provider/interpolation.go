@@ -0,0 +1,178 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/chromedp/chromedp"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// valuePlaceholder matches placeholders of values caught by previous actions, like "${values.csrf}".
+var valuePlaceholder = regexp.MustCompile(`\$\{values\.([^}]+)\}`)
+
+// nodePlaceholder matches placeholders of the node iterated by for_each_node action: "${node}" and "${node.index}".
+var nodePlaceholder = regexp.MustCompile(`\$\{node(\.index)?\}`)
+
+// hasPlaceholder reports whether the argument refers to caught values or to the iterated node.
+func hasPlaceholder(arg string) bool {
+	return valuePlaceholder.MatchString(arg) || nodePlaceholder.MatchString(arg)
+}
+
+// hasPlaceholders reports whether any argument of the action refers to caught values or to the iterated node.
+func hasPlaceholders(args []types.String) bool {
+	for _, arg := range args {
+		if hasPlaceholder(arg.ValueString()) {
+			return true
+		}
+	}
+	return false
+}
+
+// placeholderValueNames returns names of values referred by the argument.
+func placeholderValueNames(arg string) []string {
+	var names []string
+	for _, match := range valuePlaceholder.FindAllStringSubmatch(arg, -1) {
+		names = append(names, match[1])
+	}
+	return names
+}
+
+// withUnknownPlaceholders returns the arguments where arguments with placeholders are unknown,
+// so they pass the parsing like values which are not known during config validation.
+func withUnknownPlaceholders(args []types.String) []types.String {
+	if !hasPlaceholders(args) {
+		return args
+	}
+	result := make([]types.String, len(args))
+	for i, arg := range args {
+		if hasPlaceholder(arg.ValueString()) {
+			arg = types.StringUnknown()
+		}
+		result[i] = arg
+	}
+	return result
+}
+
+// checkPlaceholders checks that placeholders refer to values caught by previous actions.
+// It returns the index of the wrong argument.
+func checkPlaceholders(args []types.String, caught map[string]bool) (int, error) {
+	for i, arg := range args {
+		for _, name := range placeholderValueNames(arg.ValueString()) {
+			if !caught[name] {
+				return i, fmt.Errorf("value %q is not caught by previous actions", name)
+			}
+		}
+	}
+	return 0, nil
+}
+
+// checkNodePlaceholders checks that node placeholders are used only by actions nested in for_each_node action.
+// It returns the index of the wrong argument.
+func checkNodePlaceholders(args []types.String, inLoop bool) (int, error) {
+	if inLoop {
+		return 0, nil
+	}
+	for i, arg := range args {
+		if match := nodePlaceholder.FindString(arg.ValueString()); match != "" {
+			return i, fmt.Errorf("%s placeholder is used outside of for_each_node action", match)
+		}
+	}
+	return 0, nil
+}
+
+// interpolateNode replaces node placeholders in the argument with the selector and the index of the iterated node.
+func interpolateNode(arg string, scope *nodeScope) (string, error) {
+	var err error
+	result := nodePlaceholder.ReplaceAllStringFunc(arg, func(placeholder string) string {
+		switch {
+		case scope == nil:
+			err = fmt.Errorf("%s placeholder is used outside of for_each_node action", placeholder)
+			return placeholder
+		case placeholder == "${node.index}":
+			return strconv.Itoa(scope.index)
+		default:
+			return scope.selector
+		}
+	})
+	return result, err
+}
+
+// interpolateValues replaces placeholders in the argument with values caught by previous actions.
+// Values are placed as into "values" attribute, JSON-encoded unless they are strings.
+func interpolateValues(arg string, values map[string]interface{}) (string, error) {
+	var err error
+	result := valuePlaceholder.ReplaceAllStringFunc(arg, func(placeholder string) string {
+		name := valuePlaceholder.FindStringSubmatch(placeholder)[1]
+		value, ok := values[name]
+		if !ok {
+			err = fmt.Errorf("value %q is not caught by previous actions", name)
+			return placeholder
+		}
+		if sensitive, ok := value.(sensitiveOutput); ok {
+			value = sensitive.value
+		}
+		str, _, encodeErr := outputValue(name, value)
+		if encodeErr != nil {
+			err = encodeErr
+		}
+		return str
+	})
+	return result, err
+}
+
+// placedSecrets returns sensitive values referred by the argument as they are placed into it.
+func placedSecrets(arg string, values map[string]interface{}) []string {
+	var secrets []string
+	for _, name := range placeholderValueNames(arg) {
+		sensitive, ok := values[name].(sensitiveOutput)
+		if !ok {
+			continue
+		}
+		if str, _, err := outputValue(name, sensitive.value); err == nil {
+			secrets = appendSecret(secrets, str)
+		}
+	}
+	return secrets
+}
+
+// interpolatedAction builds the action when it's run, after placeholders in its arguments are replaced with caught values
+// and the node iterated by the enclosing for_each_node action.
+// The result of the action is placed into values like the result of the action built in advance.
+// Placed sensitive values and resolved secret arguments are stored into secrets, so they are masked in logs and errors.
+func interpolatedAction(def actionDefinition, values map[string]interface{}, secrets *[]string) chromedp.Action {
+	secretArgs := def.secretArgs()
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		*secrets = (*secrets)[:0]
+		resolved := make([]types.String, len(def.args))
+		for i, arg := range def.args {
+			value, err := interpolateNode(arg.ValueString(), nodeScopeFrom(ctx))
+			if err != nil {
+				return err
+			}
+			*secrets = append(*secrets, placedSecrets(value, values)...)
+			value, err = interpolateValues(value, values)
+			if err != nil {
+				return err
+			}
+			if secretArgs[i] {
+				*secrets = appendSecret(*secrets, value)
+			}
+			resolved[i] = types.StringValue(value)
+		}
+
+		action, err := def.build(resolved)
+		if err != nil {
+			// Errors of the builder may repeat the resolved arguments, so only the argument with placeholders is shown.
+			var argErr *actionArgError
+			if errors.As(err, &argErr) {
+				return fmt.Errorf("argument %d %q is wrong after values are placed into it", argErr.index, def.args[argErr.index].ValueString())
+			}
+			return errors.New("wrong action after values are placed into arguments")
+		}
+		return action.Action(values).Do(ctx)
+	})
+}
@@ -0,0 +1,130 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/chromedp/chromedp"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInterpolateValues(t *testing.T) {
+	id := "42"
+	token := "secret"
+	values := map[string]interface{}{
+		"id":     &id,
+		"token":  sensitiveOutput{value: &token},
+		"config": &evaluateOutput{raw: []byte(`{"a":1}`)},
+	}
+
+	result, err := interpolateValues("https://example.com/items/${values.id}/edit?token=${values.token}", values)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://example.com/items/42/edit?token=secret", result)
+
+	result, err = interpolateValues("${values.config}", values)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"a":1}`, result)
+
+	result, err = interpolateValues("no placeholders, ${id}", values)
+	assert.NoError(t, err)
+	assert.Equal(t, "no placeholders, ${id}", result)
+
+	_, err = interpolateValues("${values.missing}", values)
+	assert.EqualError(t, err, `value "missing" is not caught by previous actions`)
+}
+
+func TestWithUnknownPlaceholders(t *testing.T) {
+	args := testArgs("sleep", "${values.delay}")
+	assert.Equal(t, []types.String{types.StringValue("sleep"), types.StringUnknown()}, withUnknownPlaceholders(args))
+	assert.Equal(t, testArgs("sleep", "1s"), withUnknownPlaceholders(testArgs("sleep", "1s")))
+
+	actions := types.ListValueMust(types.ListType{ElemType: types.StringType}, []attr.Value{
+		types.ListValueMust(types.StringType, []attr.Value{types.StringValue("sleep"), types.StringValue("${values.delay}")}),
+	})
+	assert.False(t, validateActions(actions, path.Root("actions")).HasError())
+}
+
+func TestBuildActionsPlaceholders(t *testing.T) {
+	defs := []actionDefinition{
+		{args: testArgs("text", "#item-id", "id"), path: path.Root("actions").AtListIndex(0), positional: true},
+		{args: testArgs("navigate", "https://example.com/items/${values.id}"), path: path.Root("actions").AtListIndex(1), positional: true},
+		{args: testArgs("navigate", "https://example.com/items/${values.item}"), path: path.Root("actions").AtListIndex(2), positional: true},
+	}
+	steps, diags := buildActions(context.Background(), defs, map[string]interface{}{})
+	assert.Len(t, steps, 2)
+	assert.Equal(t, `actions[1]: navigate "https://example.com/items/${values.id}"`, steps[1].name)
+	if assert.Equal(t, 1, diags.ErrorsCount()) {
+		assert.Contains(t, diags[0].Detail(), `value "item" is not caught by previous actions`)
+		assert.True(t, diags[0].(diag.DiagnosticWithPath).Path().Equal(path.Root("actions").AtListIndex(2).AtListIndex(1)))
+	}
+}
+
+func TestInterpolatedAction(t *testing.T) {
+	delay := "10ms"
+	values := map[string]interface{}{"delay": &delay}
+
+	assert.NoError(t, testInterpolatedAction(values, "sleep", "${values.delay}").Do(context.Background()))
+
+	delay = "soon"
+	assert.EqualError(t, testInterpolatedAction(values, "sleep", "${values.delay}").Do(context.Background()), `argument 1 "${values.delay}" is wrong after values are placed into it`)
+
+	assert.ErrorContains(t, testInterpolatedAction(values, "sleep", "${values.missing}").Do(context.Background()), `value "missing"`)
+}
+
+func testInterpolatedAction(values map[string]interface{}, args ...string) chromedp.Action {
+	return interpolatedAction(actionDefinition{args: testArgs(args...)}, values, new([]string))
+}
+
+func TestInterpolatedActionSecrets(t *testing.T) {
+	token := "s3cr3t"
+	query := "hunter2"
+	values := map[string]interface{}{
+		"token": sensitiveOutput{value: &token},
+		"query": &query,
+	}
+
+	// The builder error repeats the resolved arguments, so it isn't shown.
+	var secrets []string
+	err := interpolatedAction(actionDefinition{args: testArgs("sleep", "${values.token}", "extra")}, values, &secrets).Do(context.Background())
+	if assert.Error(t, err) {
+		assert.NotContains(t, err.Error(), token)
+	}
+	assert.Equal(t, []string{token}, secrets)
+
+	// Values typed by send_keys are secrets even if they aren't sensitive values.
+	err = interpolatedAction(actionDefinition{args: testArgs("send_keys", "#q", "${values.query}", "timeout=never")}, values, &secrets).Do(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, []string{query}, secrets)
+}
+
+func TestInterpolateNode(t *testing.T) {
+	scope := &nodeScope{selector: `[data-chromedp-node="3-1"]`, index: 1}
+
+	result, err := interpolateNode("${node} .version", scope)
+	assert.NoError(t, err)
+	assert.Equal(t, `[data-chromedp-node="3-1"] .version`, result)
+
+	result, err = interpolateNode("version_${node.index}_${values.node}", scope)
+	assert.NoError(t, err)
+	assert.Equal(t, "version_1_${values.node}", result)
+
+	_, err = interpolateNode("${node.index}", nil)
+	assert.EqualError(t, err, "${node.index} placeholder is used outside of for_each_node action")
+
+	i, err := checkNodePlaceholders(testArgs("text", "${node}", "title"), false)
+	assert.Equal(t, 1, i)
+	assert.Error(t, err)
+	_, err = checkNodePlaceholders(testArgs("text", "${node}", "title"), true)
+	assert.NoError(t, err)
+}
+
+func TestInterpolatedActionNode(t *testing.T) {
+	ctx := withNodeScope(context.Background(), &nodeScope{index: 5})
+	assert.NoError(t, testInterpolatedAction(map[string]interface{}{}, "sleep", "${node.index}ms").Do(ctx))
+
+	assert.ErrorContains(t, testInterpolatedAction(map[string]interface{}{}, "sleep", "${node.index}ms").Do(context.Background()), "outside of for_each_node")
+}
@@ -0,0 +1,135 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// caughtValuesDescription describes "values" attribute of data sources and ephemeral resources.
+const caughtValuesDescription = `Map of output values from **value**, **text**, **evaluate** and other actions catching values.
+Values which are not strings (lists, numbers, objects) are JSON-encoded.`
+
+// valuesAttribute is the schema of "values" attribute filled by outputValues.
+func valuesAttribute(description string) schema.MapAttribute {
+	return schema.MapAttribute{
+		ElementType:         types.StringType,
+		Computed:            true,
+		MarkdownDescription: description,
+	}
+}
+
+// jsonValuesAttribute is the schema of "json_values" attribute filled by outputValues.
+func jsonValuesAttribute() schema.MapAttribute {
+	return schema.MapAttribute{
+		ElementType:         types.StringType,
+		Computed:            true,
+		MarkdownDescription: "Map of all output values JSON-encoded, including strings. Use jsondecode() to get lists, numbers and objects.",
+	}
+}
+
+// sensitiveValuesAttribute is the schema of "sensitive_values" attribute filled by sensitiveOutputValues.
+func sensitiveValuesAttribute() schema.MapAttribute {
+	return schema.MapAttribute{
+		ElementType: types.StringType,
+		Computed:    true,
+		Sensitive:   true,
+		MarkdownDescription: `Map of output values caught by actions with "sensitive" option, like **values**.
+They are not placed into **values** and **json_values**.`,
+	}
+}
+
+// sensitiveOutput is the result of the action with "sensitive" option, it's placed into "sensitive_values" only.
+type sensitiveOutput struct {
+	value interface{}
+}
+
+// outputValues converts results caught by actions into "values" and "json_values" attributes.
+// Results of *string type are placed into "values" as is, fmt.Stringer results are placed as returned by String,
+// other results are placed JSON-encoded. All results are JSON-encoded in "json_values".
+// Sensitive results are skipped, see sensitiveOutputValues.
+func outputValues(values map[string]interface{}) (map[string]string, map[string]string, error) {
+	strValues := make(map[string]string, len(values))
+	jsonValues := make(map[string]string, len(values))
+
+	for name, value := range values {
+		if _, ok := value.(sensitiveOutput); ok {
+			continue
+		}
+		str, encoded, err := outputValue(name, value)
+		if err != nil {
+			return nil, nil, err
+		}
+		strValues[name] = str
+		jsonValues[name] = encoded
+	}
+	return strValues, jsonValues, nil
+}
+
+// sensitiveOutputValues converts sensitive results caught by actions into "sensitive_values" attribute.
+// Results are placed as into "values".
+func sensitiveOutputValues(values map[string]interface{}) (map[string]string, error) {
+	sensitiveValues := map[string]string{}
+	for name, value := range values {
+		sensitive, ok := value.(sensitiveOutput)
+		if !ok {
+			continue
+		}
+		str, _, err := outputValue(name, sensitive.value)
+		if err != nil {
+			return nil, err
+		}
+		sensitiveValues[name] = str
+	}
+	return sensitiveValues, nil
+}
+
+// outputValue returns the result for "values" attribute and the JSON-encoded result.
+func outputValue(name string, value interface{}) (string, string, error) {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return "", "", fmt.Errorf("can't encode value %q: %w", name, err)
+	}
+
+	switch v := value.(type) {
+	case *string:
+		return *v, string(encoded), nil
+	case fmt.Stringer:
+		return v.String(), string(encoded), nil
+	}
+	return string(encoded), string(encoded), nil
+}
+
+// outputMaps converts results caught by actions into values of "values" and "json_values" attributes.
+func outputMaps(ctx context.Context, values map[string]interface{}) (types.Map, types.Map, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	strValues, jsonValues, err := outputValues(values)
+	if err != nil {
+		diags.AddError("can't process output values", err.Error())
+	}
+
+	strMap, d := types.MapValueFrom(ctx, types.StringType, strValues)
+	diags.Append(d...)
+	jsonMap, d := types.MapValueFrom(ctx, types.StringType, jsonValues)
+	diags.Append(d...)
+	return strMap, jsonMap, diags
+}
+
+// sensitiveOutputMap converts sensitive results caught by actions into value of "sensitive_values" attribute.
+func sensitiveOutputMap(ctx context.Context, values map[string]interface{}) (types.Map, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	sensitiveValues, err := sensitiveOutputValues(values)
+	if err != nil {
+		diags.AddError("can't process output values", err.Error())
+	}
+
+	sensitiveMap, d := types.MapValueFrom(ctx, types.StringType, sensitiveValues)
+	diags.Append(d...)
+	return sensitiveMap, diags
+}
@@ -0,0 +1,55 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutputValues(t *testing.T) {
+	text := "hello"
+	list := []string{"v1.0.0", "v1.1.0"}
+	values := map[string]interface{}{
+		"text":     &text,
+		"list":     &list,
+		"evaluate": &evaluateOutput{raw: []byte(`"evaluated"`)},
+		"object":   &evaluateOutput{raw: []byte(`{"a":1}`)},
+	}
+
+	strValues, jsonValues, err := outputValues(values)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"text":     "hello",
+		"list":     `["v1.0.0","v1.1.0"]`,
+		"evaluate": "evaluated",
+		"object":   `{"a":1}`,
+	}, strValues)
+	assert.Equal(t, map[string]string{
+		"text":     `"hello"`,
+		"list":     `["v1.0.0","v1.1.0"]`,
+		"evaluate": `"evaluated"`,
+		"object":   `{"a":1}`,
+	}, jsonValues)
+}
+
+func TestSensitiveOutputValues(t *testing.T) {
+	text := "hello"
+	token := "secret"
+	values := map[string]interface{}{
+		"text":   &text,
+		"token":  sensitiveOutput{value: &token},
+		"claims": sensitiveOutput{value: &evaluateOutput{raw: []byte(`{"sub":"admin"}`)}},
+	}
+
+	strValues, jsonValues, err := outputValues(values)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"text": "hello"}, strValues)
+	assert.Equal(t, map[string]string{"text": `"hello"`}, jsonValues)
+
+	sensitiveValues, err := sensitiveOutputValues(values)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"token":  "secret",
+		"claims": `{"sub":"admin"}`,
+	}, sensitiveValues)
+}
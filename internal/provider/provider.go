@@ -2,18 +2,29 @@ package provider
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/chromedp/chromedp"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
 // Ensure ChromedpProvider satisfies various provider interfaces.
-var _ provider.Provider = &ChromedpProvider{}
+var (
+	_ provider.Provider                       = &ChromedpProvider{}
+	_ provider.ProviderWithEphemeralResources = &ChromedpProvider{}
+)
 
 // ChromedpProvider defines the provider implementation.
 type ChromedpProvider struct {
@@ -21,15 +32,131 @@ type ChromedpProvider struct {
 	// provider is built and ran locally, and "test" when running acceptance
 	// testing.
 	version string
+
+	pool *browserPool
 }
 
 type providerData struct {
-	ctxCreator ctxCreatorFunc
+	pool *browserPool
+	// defaultActionTimeout limits time of every action without its own timeout, 0 means no limit.
+	defaultActionTimeout time.Duration
+}
+
+// runOptions returns options of running actions of the data source or resource.
+func (d *providerData) runOptions(timeout types.String, artifactsDir types.String, retry *RetryModel) (runOptions, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	opts := runOptions{
+		actionTimeout: d.defaultActionTimeout,
+		artifactsDir:  artifactsDir.ValueString(),
+		retry:         retry.policy(),
+	}
+	if timeout.ValueString() != "" {
+		var err error
+		opts.timeout, err = time.ParseDuration(timeout.ValueString())
+		if err != nil {
+			diags.AddAttributeError(path.Root("timeout"), "Invalid Duration", err.Error())
+		}
+	}
+	return opts, diags
+}
+
+// newTab opens the browser tab for the data source or resource. The tab is closed when release is called.
+func (d *providerData) newTab(ctx context.Context) (tabCtx context.Context, release context.CancelFunc, diags diag.Diagnostics) {
+	tabCtx, release, err := d.pool.newTab(ctx)
+	if err != nil {
+		diags.AddError("Can't connect to the browser", fmt.Sprintf("%v\n\nCheck the endpoint or local Chrome options of the provider.", err))
+	}
+	return tabCtx, release, diags
 }
 
 // ChromedpProviderModel describes the provider data model.
 type ChromedpProviderModel struct {
-	Endpoint types.String `tfsdk:"endpoint"`
+	Endpoint    types.String     `tfsdk:"endpoint"`
+	Headless    types.Bool       `tfsdk:"headless"`
+	ChromePath  types.String     `tfsdk:"chrome_path"`
+	UserAgent   types.String     `tfsdk:"user_agent"`
+	WindowSize  *WindowSizeModel `tfsdk:"window_size"`
+	ProxyServer types.String     `tfsdk:"proxy_server"`
+	UserDataDir types.String     `tfsdk:"user_data_dir"`
+	DisableGPU  types.Bool       `tfsdk:"disable_gpu"`
+	NoSandbox   types.Bool       `tfsdk:"no_sandbox"`
+	ExtraFlags  types.Map        `tfsdk:"extra_flags"`
+
+	MaxParallelTabs types.Int64  `tfsdk:"max_parallel_tabs"`
+	SkipPing        types.Bool   `tfsdk:"skip_ping"`
+	ConnectTimeout  types.String `tfsdk:"connect_timeout"`
+
+	DefaultActionTimeout types.String `tfsdk:"default_action_timeout"`
+}
+
+type WindowSizeModel struct {
+	Width  types.Int64 `tfsdk:"width"`
+	Height types.Int64 `tfsdk:"height"`
+}
+
+// hasLocalChromeOptions reports whether any option of local Chrome is set.
+func (m ChromedpProviderModel) hasLocalChromeOptions() bool {
+	return !m.Headless.IsNull() || !m.ChromePath.IsNull() || !m.UserAgent.IsNull() || m.WindowSize != nil ||
+		!m.ProxyServer.IsNull() || !m.UserDataDir.IsNull() || !m.DisableGPU.IsNull() || !m.NoSandbox.IsNull() ||
+		!m.ExtraFlags.IsNull()
+}
+
+// localChromeFlags returns command line flags of local Chrome that override chromedp.DefaultExecAllocatorOptions.
+func (m ChromedpProviderModel) localChromeFlags(ctx context.Context) (map[string]interface{}, diag.Diagnostics) {
+	flags := map[string]interface{}{}
+
+	var extraFlags map[string]string
+	diags := m.ExtraFlags.ElementsAs(ctx, &extraFlags, false)
+	if diags.HasError() {
+		return nil, diags
+	}
+	for name, value := range extraFlags {
+		if b, err := strconv.ParseBool(value); err == nil {
+			flags[name] = b
+			continue
+		}
+		flags[name] = value
+	}
+
+	if !m.Headless.IsNull() {
+		flags["headless"] = m.Headless.ValueBool()
+	}
+	if !m.UserAgent.IsNull() {
+		flags["user-agent"] = m.UserAgent.ValueString()
+	}
+	if m.WindowSize != nil {
+		flags["window-size"] = fmt.Sprintf("%d,%d", m.WindowSize.Width.ValueInt64(), m.WindowSize.Height.ValueInt64())
+	}
+	if !m.ProxyServer.IsNull() {
+		flags["proxy-server"] = m.ProxyServer.ValueString()
+	}
+	if !m.UserDataDir.IsNull() {
+		flags["user-data-dir"] = m.UserDataDir.ValueString()
+	}
+	if !m.DisableGPU.IsNull() {
+		flags["disable-gpu"] = m.DisableGPU.ValueBool()
+	}
+	if !m.NoSandbox.IsNull() {
+		flags["no-sandbox"] = m.NoSandbox.ValueBool()
+	}
+	return flags, diags
+}
+
+// execAllocatorOptions translates local Chrome settings into chromedp.ExecAllocatorOption.
+func (m ChromedpProviderModel) execAllocatorOptions(ctx context.Context) ([]chromedp.ExecAllocatorOption, diag.Diagnostics) {
+	flags, diags := m.localChromeFlags(ctx)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	var opts []chromedp.ExecAllocatorOption
+	if m.ChromePath.ValueString() != "" {
+		opts = append(opts, chromedp.ExecPath(m.ChromePath.ValueString()))
+	}
+	for name, value := range flags {
+		opts = append(opts, chromedp.Flag(name, value))
+	}
+	return opts, diags
 }
 
 func (p *ChromedpProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -39,6 +166,8 @@ func (p *ChromedpProvider) Metadata(ctx context.Context, req provider.MetadataRe
 
 func (p *ChromedpProvider) Schema(ctx context.Context, req provider.SchemaRequest, resp *provider.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		Description: `Runs chromedp recipes in a local or remote browser.
+The browser is launched or connected only when the first data source or resource needs it, not when the provider is configured.`,
 		Attributes: map[string]schema.Attribute{
 			"endpoint": schema.StringAttribute{
 				Description: `URL to chromedp websocket. Must be like "ws://hostname" or "ws://hostname:port".
@@ -46,10 +175,83 @@ Can be set through CHROMEDP_ENDPOINT environment variable.
 If no endpoint is defined, chromedp launches existing installation of chrome (google-chrome) from $PATH.`,
 				Optional: true,
 			},
+			"headless": schema.BoolAttribute{
+				Description: "Run local Chrome in headless mode. Defaults to true.",
+				Optional:    true,
+			},
+			"chrome_path": schema.StringAttribute{
+				Description: "Path to Chrome executable. If not set, chromedp looks for known Chrome installations in $PATH.",
+				Optional:    true,
+			},
+			"user_agent": schema.StringAttribute{
+				Description: "Default User-Agent header of local Chrome.",
+				Optional:    true,
+			},
+			"window_size": schema.SingleNestedAttribute{
+				Description: "Initial window size of local Chrome.",
+				Optional:    true,
+				Attributes: map[string]schema.Attribute{
+					"width": schema.Int64Attribute{
+						Description: "Window width in pixels.",
+						Required:    true,
+						Validators:  []validator.Int64{int64validator.AtLeast(1)},
+					},
+					"height": schema.Int64Attribute{
+						Description: "Window height in pixels.",
+						Required:    true,
+						Validators:  []validator.Int64{int64validator.AtLeast(1)},
+					},
+				},
+			},
+			"proxy_server": schema.StringAttribute{
+				Description: `Proxy server for local Chrome, like "http://proxy.example.com:3128" or "socks5://127.0.0.1:1080".`,
+				Optional:    true,
+			},
+			"user_data_dir": schema.StringAttribute{
+				Description: "User data directory of local Chrome. If not set, temporary directory is used and removed after the browser exits.",
+				Optional:    true,
+			},
+			"disable_gpu": schema.BoolAttribute{
+				Description: "Disable GPU process of local Chrome.",
+				Optional:    true,
+			},
+			"no_sandbox": schema.BoolAttribute{
+				Description: "Disable sandbox of local Chrome. Required to run Chrome as root, e.g. in containers. Sandbox is disabled by default when provider runs as root.",
+				Optional:    true,
+			},
+			"extra_flags": schema.MapAttribute{
+				ElementType: types.StringType,
+				Description: `Additional command line flags of local Chrome without leading "--", e.g. { "ignore-certificate-errors" = "true", "lang" = "en-US" }.
+Values "true" and "false" enable and disable the flag, other values are passed as "--name=value".`,
+				Optional: true,
+			},
+			"max_parallel_tabs": schema.Int64Attribute{
+				Description: `Maximum number of recipes running in parallel. Every recipe runs in its own tab of the browser shared by all data sources of the provider.
+If not set, number of tabs is not limited.`,
+				Optional:   true,
+				Validators: []validator.Int64{int64validator.AtLeast(1)},
+			},
+			"skip_ping": schema.BoolAttribute{
+				Description: `Do not navigate to blank page to check the browser right after the connection.
+Only the connection itself is checked then.`,
+				Optional: true,
+			},
+			"connect_timeout": schema.StringAttribute{
+				Description: fmt.Sprintf(`Timeout of launching or connecting to the browser, like "30s" or "2m". Defaults to "%s".`, defaultConnectTimeout),
+				Optional:    true,
+				Validators:  []validator.String{durationValidator{positive: true}},
+			},
+			"default_action_timeout": schema.StringAttribute{
+				Description: `Timeout of every action of recipes, like "30s" or "2m". Actions can override it with their own timeout. Not limited by default.`,
+				Optional:    true,
+				Validators:  []validator.String{durationValidator{positive: true}},
+			},
 		},
 	}
 }
 
+const defaultConnectTimeout = time.Minute
+
 func pingChrome(ctx context.Context) (bool, error) {
 	err := chromedp.Run(ctx, chromedp.Navigate("about://blank"))
 	if err != nil {
@@ -73,35 +275,76 @@ func (p *ChromedpProvider) Configure(ctx context.Context, req provider.Configure
 		endpoint = data.Endpoint.ValueString()
 	}
 
-	var ctxCreator ctxCreatorFunc
+	poolOpts := browserPoolOptions{
+		name:            "local Chrome",
+		maxParallelTabs: int(data.MaxParallelTabs.ValueInt64()),
+		connectTimeout:  defaultConnectTimeout,
+		skipPing:        data.SkipPing.ValueBool(),
+	}
+	if !data.ConnectTimeout.IsNull() {
+		timeout, err := time.ParseDuration(data.ConnectTimeout.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("connect_timeout"), "Invalid Duration", err.Error())
+			return
+		}
+		poolOpts.connectTimeout = timeout
+	}
+
+	var allocator allocatorFunc
 	if endpoint != "" {
-		ctxCreator = chromedpCtxWithRemoteChrome(endpoint)
+		poolOpts.name = fmt.Sprintf("remote Chrome at %s", endpoint)
+		if data.hasLocalChromeOptions() {
+			resp.Diagnostics.AddWarning("Local Chrome options are ignored", "Options of local Chrome have no effect when endpoint of remote Chrome is set.")
+		}
+		allocator = remoteChromeAllocator(endpoint)
 	} else {
-		ctxCreator = chromedpCtxWithLocalChrome()
+		opts, diags := data.execAllocatorOptions(ctx)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		allocator = localChromeAllocator(opts...)
 	}
-	dpCtx, cancel := ctxCreator(ctx)
-	defer cancel()
-	_, err := pingChrome(dpCtx)
-	if err != nil {
-		resp.Diagnostics.AddError("Cannot start to chromedp", err.Error())
-		return
+
+	if p.pool != nil {
+		p.pool.close()
 	}
+	// The browser is started by the first data source which needs it.
+	p.pool = newBrowserPool(allocator, poolOpts)
 
 	resourcesData := &providerData{
-		ctxCreator: ctxCreator,
+		pool: p.pool,
+	}
+	if !data.DefaultActionTimeout.IsNull() {
+		timeout, err := time.ParseDuration(data.DefaultActionTimeout.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("default_action_timeout"), "Invalid Duration", err.Error())
+			return
+		}
+		resourcesData.defaultActionTimeout = timeout
 	}
 
 	resp.DataSourceData = resourcesData
 	resp.ResourceData = resourcesData
+	resp.EphemeralResourceData = resourcesData
 }
 
 func (p *ChromedpProvider) Resources(ctx context.Context) []func() resource.Resource {
-	return []func() resource.Resource{}
+	return []func() resource.Resource{
+		NewRecipeResource,
+	}
+}
+
+func (p *ChromedpProvider) EphemeralResources(ctx context.Context) []func() ephemeral.EphemeralResource {
+	return []func() ephemeral.EphemeralResource{
+		NewRecipeEphemeralResource,
+	}
 }
 
 func (p *ChromedpProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
 		NewRecipeDataSource,
+		NewSessionDataSource,
 	}
 }
 
@@ -0,0 +1,55 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChromedpProviderModel_localChromeFlags(t *testing.T) {
+	model := ChromedpProviderModel{
+		Headless:    types.BoolValue(false),
+		UserAgent:   types.StringValue("terraform"),
+		WindowSize:  &WindowSizeModel{Width: types.Int64Value(1920), Height: types.Int64Value(1080)},
+		ProxyServer: types.StringValue("http://proxy:3128"),
+		UserDataDir: types.StringNull(),
+		DisableGPU:  types.BoolNull(),
+		NoSandbox:   types.BoolValue(true),
+		ExtraFlags: types.MapValueMust(types.StringType, map[string]attr.Value{
+			"lang":                      types.StringValue("en-US"),
+			"ignore-certificate-errors": types.StringValue("true"),
+			"headless":                  types.StringValue("true"),
+		}),
+	}
+
+	flags, diags := model.localChromeFlags(context.Background())
+	assert.False(t, diags.HasError())
+	assert.Equal(t, map[string]interface{}{
+		"headless":                  false,
+		"user-agent":                "terraform",
+		"window-size":               "1920,1080",
+		"proxy-server":              "http://proxy:3128",
+		"no-sandbox":                true,
+		"lang":                      "en-US",
+		"ignore-certificate-errors": true,
+	}, flags)
+	assert.True(t, model.hasLocalChromeOptions())
+
+	empty := ChromedpProviderModel{
+		Headless:    types.BoolNull(),
+		ChromePath:  types.StringNull(),
+		UserAgent:   types.StringNull(),
+		ProxyServer: types.StringNull(),
+		UserDataDir: types.StringNull(),
+		DisableGPU:  types.BoolNull(),
+		NoSandbox:   types.BoolNull(),
+		ExtraFlags:  types.MapNull(types.StringType),
+	}
+	flags, diags = empty.localChromeFlags(context.Background())
+	assert.False(t, diags.HasError())
+	assert.Empty(t, flags)
+	assert.False(t, empty.hasLocalChromeOptions())
+}
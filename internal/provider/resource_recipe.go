@@ -0,0 +1,460 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chromedp/chromedp"
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ resource.Resource                   = &RecipeResource{}
+	_ resource.ResourceWithConfigure      = &RecipeResource{}
+	_ resource.ResourceWithValidateConfig = &RecipeResource{}
+)
+
+// recipeResourceActions are attributes of the resource with actions, in the order of the resource lifecycle.
+var recipeResourceActions = []string{"create_actions", "read_actions", "update_actions", "delete_actions"}
+
+func NewRecipeResource() resource.Resource {
+	return &RecipeResource{}
+}
+
+// RecipeResource manages settings which have only web UI by running actions on create, read, update and delete.
+type RecipeResource struct {
+	data *providerData
+}
+
+type RecipeResourceModel struct {
+	CreateActions       [][]types.String `tfsdk:"create_actions"`
+	ReadActions         [][]types.String `tfsdk:"read_actions"`
+	UpdateActions       [][]types.String `tfsdk:"update_actions"`
+	DeleteActions       [][]types.String `tfsdk:"delete_actions"`
+	ExpectedValues      types.Map        `tfsdk:"expected_values"`
+	Values              types.Map        `tfsdk:"values"`
+	JSONValues          types.Map        `tfsdk:"json_values"`
+	SensitiveValues     types.Map        `tfsdk:"sensitive_values"`
+	Id                  types.String     `tfsdk:"id"`
+	Session             types.String     `tfsdk:"session"`
+	FailureArtifactsDir types.String     `tfsdk:"failure_artifacts_dir"`
+	Timeout             types.String     `tfsdk:"timeout"`
+	Retry               *RetryModel      `tfsdk:"retry"`
+}
+
+func (r *RecipeResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_recipe"
+}
+
+// resourceActionsAttribute is the schema of the resource attribute with actions.
+func resourceActionsAttribute(required bool, description string) schema.ListAttribute {
+	return schema.ListAttribute{
+		ElementType: types.ListType{
+			ElemType: types.StringType,
+		},
+		Required:            required,
+		Optional:            !required,
+		MarkdownDescription: description,
+		// Empty lists would do nothing, so even the required create_actions must have an action.
+		Validators: []validator.List{listvalidator.SizeAtLeast(1)},
+	}
+}
+
+func (r *RecipeResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: `Recipe resource manages settings which can be changed only in web UI.
+It runs **create_actions** when the resource is created, **update_actions** when it's changed and **delete_actions** when it's destroyed.
+**read_actions** run after create and update actions and on every refresh, values they catch are used for drift detection:
+if a value listed in **expected_values** differs from the value read from the page, the resource is updated.
+
+Actions are the same as actions of **chromedp_recipe** data source, but only in the list of strings form of its **actions**:
+typed **steps** aren't supported by the resource. Every operation runs in new browser tab,
+read actions following create and update actions run in the same tab.
+If read actions fail after create actions succeeded, the resource is saved as tainted and replaced on the next apply.
+Actions can refer to values of the state with "${values.<name>}" placeholders, e.g. to the id of the item caught by create actions.`,
+		Attributes: map[string]schema.Attribute{
+			"create_actions": resourceActionsAttribute(true, "List of actions creating the resource."),
+			"read_actions": resourceActionsAttribute(false, `List of actions reading the resource. Values caught by them are placed into **values** and compared with **expected_values**.
+If not set, the resource is never refreshed.`),
+			"update_actions": resourceActionsAttribute(false, "List of actions updating the resource. If not set, **create_actions** run on update."),
+			"delete_actions": resourceActionsAttribute(false, "List of actions deleting the resource. If not set, the resource is only removed from the state."),
+			"expected_values": schema.MapAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				MarkdownDescription: `Values which **read_actions** must catch after create and update actions.
+If values caught on refresh differ, the resource is updated.`,
+			},
+			"values": resourceAttribute(valuesAttribute(`Map of output values caught by actions. Values caught by create and update actions are kept until they are caught again.
+Values which are not strings (lists, numbers, objects) are JSON-encoded.`)),
+			"json_values":      resourceAttribute(jsonValuesAttribute()),
+			"sensitive_values": resourceAttribute(sensitiveValuesAttribute()),
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Random id of the resource.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"session": schema.StringAttribute{
+				Optional:            true,
+				Sensitive:           true,
+				MarkdownDescription: "Browser session exported by **chromedp_session** data source. It's restored before every list of actions.",
+				Validators:          []validator.String{sessionValidator{}},
+			},
+			"retry":                 resourceAttribute(retryAttribute(recipeRetryDescription)),
+			"timeout":               resourceAttribute(timeoutAttribute(`Timeout of every list of actions, like "5m". Not limited by default.`)),
+			"failure_artifacts_dir": resourceAttribute(failureArtifactsDirAttribute()),
+		},
+	}
+}
+
+func (r *RecipeResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*providerData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *providerData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.data = data
+}
+
+func (r *RecipeResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	for _, name := range recipeResourceActions {
+		var actions types.List
+
+		resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root(name), &actions)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		resp.Diagnostics.Append(validateActions(actions, path.Root(name))...)
+	}
+}
+
+// run runs the lists of actions of the resource one after another in the same browser tab.
+// Values caught by actions are placed into values. finished is called with the name of every list which ran successfully.
+func (r *RecipeResource) run(ctx context.Context, data *RecipeResourceModel, values map[string]interface{}, finished func(name string), actions ...string) diag.Diagnostics {
+	lists := map[string][][]types.String{
+		"create_actions": data.CreateActions,
+		"read_actions":   data.ReadActions,
+		"update_actions": data.UpdateActions,
+		"delete_actions": data.DeleteActions,
+	}
+
+	steps, diags := restoreSessionSteps(data.Session)
+	if diags.HasError() {
+		return diags
+	}
+	// Actions are built together, so they can refer to values caught by actions of previous lists.
+	var defs []actionDefinition
+	for _, name := range actions {
+		defs = append(defs, actionListDefinitions(lists[name], path.Root(name))...)
+	}
+	actionSteps, d := buildActions(ctx, defs, values)
+	diags.Append(d...)
+	if diags.HasError() {
+		return diags
+	}
+	steps = append(steps, withListMarkers(actionSteps, finished)...)
+
+	runOpts, d := r.data.runOptions(data.Timeout, data.FailureArtifactsDir, data.Retry)
+	diags.Append(d...)
+	if diags.HasError() {
+		return diags
+	}
+
+	tabCtx, release, d := r.data.newTab(ctx)
+	diags.Append(d...)
+	if diags.HasError() {
+		return diags
+	}
+	defer release()
+
+	diags.Append(runSteps(ctx, tabCtx, steps, runOpts)...)
+	return diags
+}
+
+// withListMarkers returns steps followed by a step calling finished after the last step of every action list.
+func withListMarkers(steps []recipeStep, finished func(name string)) []recipeStep {
+	if finished == nil {
+		return steps
+	}
+	marker := func(name string) recipeStep {
+		return recipeStep{
+			action: chromedp.ActionFunc(func(context.Context) error {
+				finished(name)
+				return nil
+			}),
+			name: name + " finished",
+		}
+	}
+
+	result := make([]recipeStep, 0, len(steps)+2)
+	for i, step := range steps {
+		result = append(result, step)
+		name := actionListName(step)
+		if i == len(steps)-1 || actionListName(steps[i+1]) != name {
+			result = append(result, marker(name))
+		}
+	}
+	return result
+}
+
+// actionListName returns the name of the attribute with the action of the step.
+func actionListName(step recipeStep) string {
+	if steps := step.path.Steps(); len(steps) > 0 {
+		if name, ok := steps[0].(path.PathStepAttributeName); ok {
+			return string(name)
+		}
+	}
+	return ""
+}
+
+// apply runs the actions creating or updating the resource followed by read actions and sets computed attributes.
+// Values of prior are kept unless they are caught again.
+// Once the actions changing the resource succeed, computed attributes are set even if read actions fail, so the change is saved.
+func (r *RecipeResource) apply(ctx context.Context, data *RecipeResourceModel, prior *RecipeResourceModel, actions string) diag.Diagnostics {
+	stateValues := prior.caughtValues()
+	values := copyValues(stateValues)
+	changed := false
+	diags := r.run(ctx, data, values, func(name string) {
+		if name == actions {
+			changed = true
+		}
+	}, actions, "read_actions")
+	if !changed {
+		return diags
+	}
+
+	diags.Append(data.setOutputs(ctx, prior, caughtSince(values, stateValues))...)
+	if diags.HasError() {
+		return diags
+	}
+
+	diags.Append(checkExpectedValues(data.ExpectedValues, data.Values)...)
+	return diags
+}
+
+// setOutputs converts values caught by actions into "values", "json_values" and "sensitive_values" attributes
+// keeping other values of the prior state.
+func (m *RecipeResourceModel) setOutputs(ctx context.Context, prior *RecipeResourceModel, values map[string]interface{}) diag.Diagnostics {
+	strMap, jsonMap, diags := outputMaps(ctx, values)
+	sensitiveMap, d := sensitiveOutputMap(ctx, values)
+	diags.Append(d...)
+	if diags.HasError() {
+		return diags
+	}
+
+	m.Values, m.JSONValues, m.SensitiveValues = strMap, jsonMap, sensitiveMap
+	if prior != nil {
+		m.Values = mergeStringMaps(prior.Values, strMap)
+		m.JSONValues = mergeStringMaps(prior.JSONValues, jsonMap)
+		m.SensitiveValues = mergeStringMaps(prior.SensitiveValues, sensitiveMap)
+	}
+	return diags
+}
+
+// caughtValues returns values of the state as caught by actions, so actions can refer to them with placeholders.
+func (m *RecipeResourceModel) caughtValues() map[string]interface{} {
+	values := map[string]interface{}{}
+	if m == nil {
+		return values
+	}
+	for name, value := range m.Values.Elements() {
+		if str, ok := value.(types.String); ok {
+			values[name] = str.ValueStringPointer()
+		}
+	}
+	for name, value := range m.SensitiveValues.Elements() {
+		if str, ok := value.(types.String); ok {
+			values[name] = sensitiveOutput{value: str.ValueStringPointer()}
+		}
+	}
+	return values
+}
+
+func copyValues(values map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(values))
+	for name, value := range values {
+		result[name] = value
+	}
+	return result
+}
+
+// caughtSince returns values caught by actions after values of the state were placed into the map.
+// Values of the state are converted into strings, so they are not placed into outputs again.
+func caughtSince(values map[string]interface{}, stateValues map[string]interface{}) map[string]interface{} {
+	caught := map[string]interface{}{}
+	for name, value := range values {
+		// Results of actions are pointers, so the value of the state is replaced if the action caught the value again.
+		if stateValue, ok := stateValues[name]; !ok || stateValue != value {
+			caught[name] = value
+		}
+	}
+	return caught
+}
+
+// mergeStringMaps returns map with elements of base overridden by elements of override.
+func mergeStringMaps(base types.Map, override types.Map) types.Map {
+	elems := make(map[string]attr.Value, len(base.Elements())+len(override.Elements()))
+	for key, value := range base.Elements() {
+		elems[key] = value
+	}
+	for key, value := range override.Elements() {
+		elems[key] = value
+	}
+	return types.MapValueMust(types.StringType, elems)
+}
+
+// checkExpectedValues reports expected values which differ from the values caught by actions.
+func checkExpectedValues(expected types.Map, values types.Map) diag.Diagnostics {
+	var diags diag.Diagnostics
+	actual := values.Elements()
+	for key, value := range expected.Elements() {
+		want, ok := value.(types.String)
+		if !ok || want.IsUnknown() {
+			continue
+		}
+		got, ok := actual[key].(types.String)
+		switch {
+		case !ok:
+			diags.AddAttributeError(path.Root("expected_values").AtMapKey(key), "Unexpected value",
+				fmt.Sprintf("read_actions didn't catch value %q after the resource was changed.", key))
+		case got.ValueString() != want.ValueString():
+			diags.AddAttributeError(path.Root("expected_values").AtMapKey(key), "Unexpected value",
+				fmt.Sprintf("read_actions caught %q as value %q after the resource was changed, expected %q.", got.ValueString(), key, want.ValueString()))
+		}
+	}
+	return diags
+}
+
+// readExpectedValues returns expected values replaced by the values caught by actions, so the difference is shown in the plan.
+// Values which are not caught anymore are removed.
+func readExpectedValues(expected types.Map, values types.Map) types.Map {
+	if expected.IsNull() {
+		return expected
+	}
+	actual := values.Elements()
+	elems := make(map[string]attr.Value, len(expected.Elements()))
+	for key := range expected.Elements() {
+		if value, ok := actual[key]; ok {
+			elems[key] = value
+		}
+	}
+	return types.MapValueMust(types.StringType, elems)
+}
+
+func (r *RecipeResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data RecipeResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	id, err := uuid.GenerateUUID()
+	if err != nil {
+		resp.Diagnostics.AddError("can't generate id of the resource", err.Error())
+		return
+	}
+	data.Id = types.StringValue(id)
+
+	diags := r.apply(ctx, &data, nil, "create_actions")
+	resp.Diagnostics.Append(diags...)
+	if data.Values.IsUnknown() {
+		// create_actions failed, so nothing was created and the resource isn't saved.
+		return
+	}
+
+	// The state is saved even if read actions fail or expected values don't match,
+	// so the resource is tainted and recreated on the next apply instead of being orphaned.
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *RecipeResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data RecipeResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.ReadActions == nil {
+		return
+	}
+
+	stateValues := data.caughtValues()
+	values := copyValues(stateValues)
+	resp.Diagnostics.Append(r.run(ctx, &data, values, nil, "read_actions")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	prior := data
+	resp.Diagnostics.Append(data.setOutputs(ctx, &prior, caughtSince(values, stateValues))...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.ExpectedValues = readExpectedValues(data.ExpectedValues, data.Values)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *RecipeResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data, state RecipeResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	actions := "update_actions"
+	if data.UpdateActions == nil {
+		actions = "create_actions"
+	}
+
+	diags := r.apply(ctx, &data, &state, actions)
+	resp.Diagnostics.Append(diags...)
+	if data.Values.IsUnknown() {
+		// Update actions failed, the prior state is kept.
+		resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *RecipeResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data RecipeResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.DeleteActions == nil {
+		return
+	}
+
+	resp.Diagnostics.Append(r.run(ctx, &data, data.caughtValues(), nil, "delete_actions")...)
+}
@@ -0,0 +1,214 @@
+package provider
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccRecipeResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testPreCheck(t)
+		},
+		ProtoV6ProviderFactories: testProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + testRecipeResourceConfig("dark"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("chromedp_recipe.test", "id"),
+					resource.TestCheckResourceAttr("chromedp_recipe.test", "values.theme", "dark"),
+					resource.TestCheckResourceAttr("chromedp_recipe.test", "json_values.theme", `"dark"`),
+				),
+			},
+			{
+				Config: providerConfig + testRecipeResourceConfig("light"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("chromedp_recipe.test", "values.theme", "light"),
+				),
+			},
+		},
+	})
+}
+
+func testRecipeResourceConfig(theme string) string {
+	return `
+resource "chromedp_recipe" "test" {
+	create_actions = [
+	  ["navigate", "https://pkg.go.dev/time"],
+	  ["storage_set", "local", "theme", "` + theme + `"],
+	]
+	read_actions = [
+	  ["navigate", "https://pkg.go.dev/time"],
+	  ["storage_get", "local", "theme", "theme"],
+	]
+	delete_actions = [
+	  ["navigate", "https://pkg.go.dev/time"],
+	  ["storage_remove", "local", "theme"],
+	]
+	expected_values = {
+	  theme = "` + theme + `"
+	}
+}
+`
+}
+
+func TestAccRecipeResource_readActionsFail(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testPreCheck(t)
+		},
+		ProtoV6ProviderFactories: testProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      providerConfig + testRecipeResourceReadConfig("throw new Error('broken read')"),
+				ExpectError: regexp.MustCompile("broken read"),
+			},
+			{
+				// The resource created by the first step is saved as tainted, so it's replaced instead of created again.
+				Config: providerConfig + testRecipeResourceReadConfig("'created'"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("chromedp_recipe.test", plancheck.ResourceActionReplace),
+					},
+				},
+				Check: resource.TestCheckResourceAttr("chromedp_recipe.test", "values.status", "created"),
+			},
+		},
+	})
+}
+
+func testRecipeResourceReadConfig(expression string) string {
+	return `
+resource "chromedp_recipe" "test" {
+	create_actions = [
+	  ["evaluate", "'created'", "created"],
+	]
+	read_actions = [
+	  ["evaluate", "` + expression + `", "status"],
+	]
+}
+`
+}
+
+func TestAccRecipeResource_invalidActions(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "chromedp_recipe" "test" {
+	create_actions = [
+	  ["navigate", "https://pkg.go.dev/time"],
+	]
+	delete_actions = [
+	  ["sleep", "forever"],
+	]
+}
+`,
+				ExpectError: regexp.MustCompile("Wrong action definition"),
+			},
+			{
+				Config: `
+resource "chromedp_recipe" "test" {
+	create_actions = []
+}
+`,
+				ExpectError: regexp.MustCompile(`Attribute create_actions list must contain at least 1 elements`),
+			},
+		},
+	})
+}
+
+func TestExpectedValues(t *testing.T) {
+	expected := types.MapValueMust(types.StringType, map[string]attr.Value{
+		"theme":    types.StringValue("dark"),
+		"language": types.StringValue("en"),
+	})
+	values := types.MapValueMust(types.StringType, map[string]attr.Value{
+		"theme": types.StringValue("light"),
+		"title": types.StringValue("Settings"),
+	})
+
+	diags := checkExpectedValues(expected, values)
+	assert.Equal(t, 2, diags.ErrorsCount())
+
+	assert.Equal(t, types.MapValueMust(types.StringType, map[string]attr.Value{
+		"theme": types.StringValue("light"),
+	}), readExpectedValues(expected, values))
+	assert.True(t, readExpectedValues(types.MapNull(types.StringType), values).IsNull())
+
+	assert.False(t, checkExpectedValues(readExpectedValues(expected, values), values).HasError())
+}
+
+func TestMergeStringMaps(t *testing.T) {
+	merged := mergeStringMaps(
+		types.MapValueMust(types.StringType, map[string]attr.Value{
+			"item_id": types.StringValue("42"),
+			"theme":   types.StringValue("dark"),
+		}),
+		types.MapValueMust(types.StringType, map[string]attr.Value{
+			"theme": types.StringValue("light"),
+		}),
+	)
+	assert.Equal(t, types.MapValueMust(types.StringType, map[string]attr.Value{
+		"item_id": types.StringValue("42"),
+		"theme":   types.StringValue("light"),
+	}), merged)
+}
+
+func TestRecipeResourceModel_caughtValues(t *testing.T) {
+	var empty *RecipeResourceModel
+	assert.Empty(t, empty.caughtValues())
+
+	model := &RecipeResourceModel{
+		Values: types.MapValueMust(types.StringType, map[string]attr.Value{
+			"item_id": types.StringValue("42"),
+		}),
+		SensitiveValues: types.MapValueMust(types.StringType, map[string]attr.Value{
+			"token": types.StringValue("secret"),
+		}),
+	}
+	stateValues := model.caughtValues()
+	result, err := interpolateValues("/items/${values.item_id}?token=${values.token}", stateValues)
+	assert.NoError(t, err)
+	assert.Equal(t, "/items/42?token=secret", result)
+
+	values := copyValues(stateValues)
+	theme := "dark"
+	values["theme"] = &theme
+	itemID := "43"
+	values["item_id"] = &itemID
+	assert.Equal(t, map[string]interface{}{"theme": &theme, "item_id": &itemID}, caughtSince(values, stateValues))
+}
+
+func TestWithListMarkers(t *testing.T) {
+	steps := []recipeStep{
+		{name: "restoring session"},
+		{name: "create 0", path: path.Root("create_actions").AtListIndex(0)},
+		{name: "create 1", path: path.Root("create_actions").AtListIndex(1)},
+		{name: "read 0", path: path.Root("read_actions").AtListIndex(0)},
+	}
+	assert.Equal(t, steps, withListMarkers(steps, nil))
+
+	var finished []string
+	marked := withListMarkers(steps[1:], func(name string) {
+		finished = append(finished, name)
+	})
+	var names []string
+	for _, step := range marked {
+		names = append(names, step.name)
+		if step.action != nil {
+			assert.NoError(t, step.action.Do(context.Background()))
+		}
+	}
+	assert.Equal(t, []string{"create 0", "create 1", "create_actions finished", "read 0", "read_actions finished"}, names)
+	assert.Equal(t, []string{"create_actions", "read_actions"}, finished)
+}
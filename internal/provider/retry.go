@@ -0,0 +1,223 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// defaultRetryBackoff is the delay before the second attempt if backoff isn't set.
+const defaultRetryBackoff = time.Second
+
+// Classes of errors which can be retried.
+const (
+	errorClassTimeout    = "timeout"
+	errorClassNetwork    = "network"
+	errorClassJavaScript = "javascript"
+	errorClassOther      = "other"
+)
+
+var errorClasses = []string{errorClassTimeout, errorClassNetwork, errorClassJavaScript, errorClassOther}
+
+// retryPolicy describes how failed actions are retried. Zero fields are taken from the policy of the recipe.
+type retryPolicy struct {
+	// attempts is the total number of attempts, 1 means no retries.
+	attempts int
+	// backoff is the delay before the second attempt, it's doubled for every next attempt.
+	backoff time.Duration
+	// on lists retried classes of errors, all errors are retried if it's empty.
+	on []string
+}
+
+// merge returns the policy with fields of p overridden by fields set in override.
+func (p retryPolicy) merge(override retryPolicy) retryPolicy {
+	if override.attempts > 0 {
+		p.attempts = override.attempts
+	}
+	if override.backoff > 0 {
+		p.backoff = override.backoff
+	}
+	if len(override.on) > 0 {
+		p.on = override.on
+	}
+	return p
+}
+
+// retries reports whether the error is retried by the policy.
+func (p retryPolicy) retries(err error) bool {
+	if len(p.on) == 0 {
+		return true
+	}
+	class := errorClass(err)
+	for _, on := range p.on {
+		if on == class {
+			return true
+		}
+	}
+	return false
+}
+
+// delay returns the delay before the attempt, attempts are counted from 1.
+func (p retryPolicy) delay(attempt int) time.Duration {
+	backoff := p.backoff
+	if backoff <= 0 {
+		backoff = defaultRetryBackoff
+	}
+	for i := 2; i < attempt; i++ {
+		backoff *= 2
+	}
+	return backoff
+}
+
+// errorClass returns the class of the error returned by the action.
+func errorClass(err error) string {
+	var exception *runtime.ExceptionDetails
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return errorClassTimeout
+	case errors.As(err, &exception):
+		return errorClassJavaScript
+	case strings.Contains(err.Error(), "net::ERR_"):
+		return errorClassNetwork
+	}
+	return errorClassOther
+}
+
+// parseErrorClasses parses comma separated classes of errors.
+func parseErrorClasses(s string) ([]string, error) {
+	classes := strings.Split(s, ",")
+	for _, class := range classes {
+		if !isErrorClass(class) {
+			return nil, fmt.Errorf("unknown error class %q, expected one of %s", class, strings.Join(errorClasses, ", "))
+		}
+	}
+	return classes, nil
+}
+
+func isErrorClass(class string) bool {
+	for _, c := range errorClasses {
+		if c == class {
+			return true
+		}
+	}
+	return false
+}
+
+// parse parses the retry option of the action: "retry=<attempts>", "retry_backoff=<duration>" or "retry_on=<classes>".
+// It returns false if opt isn't a retry option.
+func (p *retryPolicy) parse(opt string) (bool, error) {
+	key, value, found := strings.Cut(opt, "=")
+	if !found {
+		return false, nil
+	}
+
+	switch key {
+	case "retry":
+		attempts, err := strconv.Atoi(value)
+		if err != nil || attempts < 1 {
+			return true, fmt.Errorf("retry option must be positive number of attempts, got %q", value)
+		}
+		p.attempts = attempts
+	case "retry_backoff":
+		backoff, err := time.ParseDuration(value)
+		if err != nil || backoff <= 0 {
+			return true, fmt.Errorf("retry_backoff option must be positive duration like \"2s\", got %q", value)
+		}
+		p.backoff = backoff
+	case "retry_on":
+		classes, err := parseErrorClasses(value)
+		if err != nil {
+			return true, err
+		}
+		p.on = classes
+	default:
+		return false, nil
+	}
+	return true, nil
+}
+
+// RetryModel is the retry policy in the configuration.
+type RetryModel struct {
+	Attempts types.Int64    `tfsdk:"attempts"`
+	Backoff  types.String   `tfsdk:"backoff"`
+	On       []types.String `tfsdk:"on"`
+}
+
+// policy converts the model into the retry policy. Values are checked by validators of the schema.
+func (m *RetryModel) policy() retryPolicy {
+	if m == nil {
+		return retryPolicy{}
+	}
+	policy := retryPolicy{attempts: int(m.Attempts.ValueInt64())}
+	if m.Backoff.ValueString() != "" {
+		policy.backoff, _ = time.ParseDuration(m.Backoff.ValueString())
+	}
+	for _, on := range m.On {
+		policy.on = append(policy.on, on.ValueString())
+	}
+	return policy
+}
+
+// args returns the retry options of the action.
+func (m *RetryModel) args() []types.String {
+	if m == nil {
+		return nil
+	}
+	var args []types.String
+	if !m.Attempts.IsNull() {
+		args = append(args, types.StringValue("retry="+strconv.FormatInt(m.Attempts.ValueInt64(), 10)))
+	}
+	if m.Backoff.ValueString() != "" {
+		args = append(args, types.StringValue("retry_backoff="+m.Backoff.ValueString()))
+	}
+	if len(m.On) > 0 {
+		on := make([]string, 0, len(m.On))
+		for _, class := range m.On {
+			on = append(on, class.ValueString())
+		}
+		args = append(args, types.StringValue("retry_on="+strings.Join(on, ",")))
+	}
+	return args
+}
+
+// recipeRetryDescription describes "retry" attribute of recipes.
+const recipeRetryDescription = "Retry policy of every action. Actions can override its attributes with their own options. Actions are not retried by default."
+
+func retryAttribute(description string) schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		Optional:            true,
+		MarkdownDescription: description,
+		Attributes: map[string]schema.Attribute{
+			"attempts": schema.Int64Attribute{
+				Required:            true,
+				MarkdownDescription: "Total number of attempts, 1 means no retries.",
+				Validators:          []validator.Int64{int64validator.AtLeast(1)},
+			},
+			"backoff": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: fmt.Sprintf(`Delay before the second attempt, doubled for every next attempt, like "2s". Defaults to "%s".`, defaultRetryBackoff),
+				Validators:          []validator.String{durationValidator{positive: true}},
+			},
+			"on": schema.ListAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				MarkdownDescription: `Classes of retried errors: "timeout" (the action timed out), "network" (the page failed to load),
+"javascript" (the script threw an exception) and "other". All errors are retried if not set.`,
+				Validators: []validator.List{
+					listvalidator.ValueStringsAre(stringvalidator.OneOf(errorClasses...)),
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,69 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorClass(t *testing.T) {
+	assert.Equal(t, errorClassTimeout, errorClass(fmt.Errorf("action timed out after 1s: %w", context.DeadlineExceeded)))
+	assert.Equal(t, errorClassJavaScript, errorClass(&runtime.ExceptionDetails{Text: "Uncaught"}))
+	assert.Equal(t, errorClassNetwork, errorClass(errors.New("page load error net::ERR_CONNECTION_REFUSED")))
+	assert.Equal(t, errorClassOther, errorClass(errors.New("could not find node")))
+}
+
+func TestRetryPolicy(t *testing.T) {
+	policy := retryPolicy{attempts: 3}.merge(retryPolicy{backoff: 2 * time.Second, on: []string{errorClassTimeout}})
+	assert.Equal(t, retryPolicy{attempts: 3, backoff: 2 * time.Second, on: []string{errorClassTimeout}}, policy)
+
+	assert.Equal(t, 2*time.Second, policy.delay(2))
+	assert.Equal(t, 8*time.Second, policy.delay(4))
+	assert.Equal(t, defaultRetryBackoff, retryPolicy{}.delay(2))
+
+	assert.True(t, policy.retries(context.DeadlineExceeded))
+	assert.False(t, policy.retries(errors.New("could not find node")))
+	assert.True(t, retryPolicy{}.retries(errors.New("could not find node")))
+}
+
+func TestActionBuilderRetry(t *testing.T) {
+	action, err := actionBuilder(testArgs("click", "#submit", "timeout=5s", "retry=3", "retry_backoff=2s", "retry_on=timeout,network"))
+	assert.NoError(t, err)
+	assert.Equal(t, 5*time.Second, action.timeout)
+	assert.Equal(t, retryPolicy{attempts: 3, backoff: 2 * time.Second, on: []string{"timeout", "network"}}, action.retry)
+
+	for _, args := range [][]string{
+		{"click", "#submit", "retry=0"},
+		{"click", "#submit", "retry_backoff=never"},
+		{"click", "#submit", "retry_on=flaky"},
+	} {
+		_, err := actionBuilder(testArgs(args...))
+		assert.Error(t, err, args)
+	}
+
+	// Typed values are required arguments, they are never taken for retry options.
+	for _, args := range [][]string{
+		{"send_keys", "#q", "retry=3"},
+		{"set_value", "#f", "retry_backoff=2s"},
+		{"storage_set", "local", "config", "retry_on=timeout"},
+	} {
+		action, err := actionBuilder(testArgs(args...))
+		if assert.NoError(t, err, args) {
+			assert.Equal(t, retryPolicy{}, action.retry, args)
+		}
+	}
+
+	model := &RetryModel{
+		Attempts: types.Int64Value(3),
+		Backoff:  types.StringValue("2s"),
+		On:       []types.String{types.StringValue("timeout"), types.StringValue("network")},
+	}
+	assert.Equal(t, testArgs("retry=3", "retry_backoff=2s", "retry_on=timeout,network"), model.args())
+	assert.Equal(t, action.retry, model.policy())
+}
@@ -0,0 +1,403 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chromedp/chromedp"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// maxDescribedArgLength limits length of arguments shown in logs and diagnostics.
+const maxDescribedArgLength = 64
+
+// redactedArg replaces sensitive arguments in logs and diagnostics.
+const redactedArg = "(sensitive)"
+
+// secretArgIndexes are indexes of arguments typed into the page or stored by the browser, by the verb of the action.
+// Such arguments are redacted in logs and diagnostics, as all arguments of actions with "sensitive" option.
+var secretArgIndexes = map[string]int{
+	"set_value":   2,
+	"send_keys":   2,
+	"cookie":      2,
+	"storage_set": 3,
+}
+
+// recipeStep is the action run by data sources together with its description for logs and diagnostics.
+type recipeStep struct {
+	action chromedp.Action
+	// name describes the action, like `actions[1]: wait_visible "body footer"`.
+	name string
+	// path is the configuration path of the action. It's empty for actions added by the data source itself.
+	path path.Path
+	// timeout overrides the default action timeout, 0 means the default.
+	timeout time.Duration
+	// retry overrides fields of the retry policy of the recipe.
+	retry retryPolicy
+	// secrets are values of sensitive arguments masked in logs.
+	secrets []string
+	// placedSecrets are secrets known only when the action runs, like sensitive values placed into its arguments.
+	placedSecrets *[]string
+	// block is set for if_present, if_visible and for_each_node actions, children are actions nested in the block.
+	block    *actionBlock
+	children []recipeStep
+}
+
+// allSecrets returns secrets of the step including secrets placed into its arguments by the last run.
+func (s recipeStep) allSecrets() []string {
+	if s.placedSecrets == nil || len(*s.placedSecrets) == 0 {
+		return s.secrets
+	}
+	return append(append([]string(nil), s.secrets...), *s.placedSecrets...)
+}
+
+// logContext returns ctx masking secrets of the step in fields of logs.
+func (s recipeStep) logContext(ctx context.Context) context.Context {
+	secrets := s.allSecrets()
+	if len(secrets) == 0 {
+		return ctx
+	}
+	return tflog.MaskAllFieldValuesStrings(ctx, secrets...)
+}
+
+// redact replaces secrets of the step in the text, like the error of the action.
+func (s recipeStep) redact(text string) string {
+	for _, secret := range s.allSecrets() {
+		text = strings.ReplaceAll(text, secret, redactedArg)
+	}
+	return text
+}
+
+// runOptions configures runSteps.
+type runOptions struct {
+	// timeout limits time of all steps, 0 means no limit.
+	timeout time.Duration
+	// actionTimeout limits time of every step without its own timeout, 0 means no limit.
+	actionTimeout time.Duration
+	// artifactsDir is the directory for failure artifacts, empty means no artifacts.
+	artifactsDir string
+	// retry is the retry policy of every step, steps can override its fields.
+	retry retryPolicy
+}
+
+// sensitive reports whether the action has "sensitive" option.
+// Actions which can't be built are sensitive if any of their arguments is "sensitive", so their arguments aren't shown either.
+func (a actionDefinition) sensitive() bool {
+	action, err := a.build(withUnknownPlaceholders(a.args))
+	if err == nil {
+		return action.sensitive
+	}
+	for _, arg := range a.args[1:] {
+		if arg.ValueString() == "sensitive" {
+			return true
+		}
+	}
+	return false
+}
+
+// secretArgs reports which arguments of the action are sensitive, the verb is never sensitive.
+func (a actionDefinition) secretArgs() []bool {
+	secret := make([]bool, len(a.args))
+	if len(a.args) == 0 {
+		return secret
+	}
+	if a.sensitive() {
+		for i := 1; i < len(a.args); i++ {
+			secret[i] = true
+		}
+		return secret
+	}
+	if i, ok := secretArgIndexes[a.args[0].ValueString()]; ok && i < len(a.args) {
+		secret[i] = true
+	}
+	return secret
+}
+
+// redactedArgs returns arguments of the action with sensitive arguments replaced.
+func (a actionDefinition) redactedArgs() []string {
+	secret := a.secretArgs()
+	args := make([]string, 0, len(a.args))
+	for i, arg := range a.args {
+		if secret[i] {
+			args = append(args, redactedArg)
+		} else {
+			args = append(args, arg.ValueString())
+		}
+	}
+	return args
+}
+
+// appendSecret appends the secret unless it's empty: empty strings can't be masked, they would match every position of the log.
+func appendSecret(secrets []string, secret string) []string {
+	if secret == "" {
+		return secrets
+	}
+	return append(secrets, secret)
+}
+
+// secrets returns values of sensitive arguments of the action.
+func (a actionDefinition) secrets() []string {
+	var secrets []string
+	for i, secret := range a.secretArgs() {
+		if secret {
+			secrets = appendSecret(secrets, a.args[i].ValueString())
+		}
+	}
+	return secrets
+}
+
+// describe returns the verb and the arguments of the action, long arguments are truncated and sensitive ones are redacted.
+func (a actionDefinition) describe() string {
+	secret := a.secretArgs()
+	parts := make([]string, 0, len(a.args))
+	for i, arg := range a.args {
+		value := arg.ValueString()
+		switch {
+		case i == 0:
+			parts = append(parts, value)
+			continue
+		case secret[i]:
+			parts = append(parts, redactedArg)
+			continue
+		}
+		if len(value) > maxDescribedArgLength {
+			value = value[:maxDescribedArgLength] + "..."
+		}
+		parts = append(parts, fmt.Sprintf("%q", value))
+	}
+	return fmt.Sprintf("%s: %s", a.path, strings.Join(parts, " "))
+}
+
+// buildActions builds steps of the definitions, values caught by actions are placed into values when they run.
+// Actions with placeholders of values caught by previous actions are built again when they are run.
+// Actions between a block action and its "end" action are nested into the step of the block.
+func buildActions(ctx context.Context, defs []actionDefinition, values map[string]interface{}) ([]recipeStep, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	steps := make([]recipeStep, 0, len(defs))
+
+	// caught are names of values caught by previous actions, they can be referred by placeholders.
+	caught := make(map[string]bool, len(values))
+	for name := range values {
+		caught[name] = true
+	}
+
+	// opened are steps of blocks not closed yet, with definitions of their actions.
+	var opened []recipeStep
+	var openedDefs []actionDefinition
+	appendStep := func(step recipeStep) {
+		if len(opened) == 0 {
+			steps = append(steps, step)
+			return
+		}
+		parent := &opened[len(opened)-1]
+		parent.children = append(parent.children, step)
+	}
+
+	tflog.Debug(ctx, "loop over actions")
+	for _, def := range defs {
+		tflog.Debug(ctx, "building actions", map[string]interface{}{"args": def.redactedArgs()})
+		action, err := def.build(withUnknownPlaceholders(def.args))
+		if err == nil {
+			if i, placeholderErr := checkPlaceholders(def.args, caught); placeholderErr != nil {
+				err = argError(i, placeholderErr)
+			} else if i, placeholderErr := checkNodePlaceholders(def.args, inNodeLoop(openedDefs)); placeholderErr != nil {
+				err = argError(i, placeholderErr)
+			}
+		}
+		if err != nil {
+			diags.AddAttributeError(def.errorPath(err), "wrong action definition", err.Error())
+			continue
+		}
+
+		if action.blockEnd {
+			if len(opened) == 0 {
+				diags.AddAttributeError(def.path, "wrong action definition", errEndWithoutBlock.Error())
+				continue
+			}
+			block := opened[len(opened)-1]
+			opened, openedDefs = opened[:len(opened)-1], openedDefs[:len(openedDefs)-1]
+			appendStep(block)
+			continue
+		}
+
+		if action.valueName != "" {
+			caught[action.valueName] = true
+		}
+		var dpAction chromedp.Action
+		var placedSecrets *[]string
+		switch {
+		case action.block != nil:
+			if hasPlaceholders(def.args) {
+				// The selector is the only argument of blocks, it's resolved when the block runs.
+				action.block.selector = def.args[1].ValueString()
+				action.block.values = values
+			}
+			dpAction = action.Action(values)
+		case hasPlaceholders(def.args):
+			placedSecrets = new([]string)
+			dpAction = interpolatedAction(def, values, placedSecrets)
+		default:
+			caughtByAction := map[string]interface{}{}
+			dpAction = placeValues(action.Action(caughtByAction), caughtByAction, values)
+		}
+		step := recipeStep{
+			action:        dpAction,
+			name:          def.describe(),
+			path:          def.path,
+			timeout:       action.timeout,
+			retry:         action.retry,
+			secrets:       def.secrets(),
+			placedSecrets: placedSecrets,
+			block:         action.block,
+		}
+		if action.block != nil {
+			opened, openedDefs = append(opened, step), append(openedDefs, def)
+			continue
+		}
+		appendStep(step)
+	}
+	for _, def := range openedDefs {
+		diags.AddAttributeError(def.path, "wrong action definition", unclosedBlockError(def.args[0].ValueString()).Error())
+	}
+	return steps, diags
+}
+
+// placeValues returns the action which places values caught by it into values after it runs,
+// so placeholders refer to values caught so far and not to results of actions which haven't run yet.
+func placeValues(action chromedp.Action, caught map[string]interface{}, values map[string]interface{}) chromedp.Action {
+	if len(caught) == 0 {
+		return action
+	}
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		if err := action.Do(ctx); err != nil {
+			return err
+		}
+		for name, value := range caught {
+			values[name] = value
+		}
+		return nil
+	})
+}
+
+// runSteps runs steps one by one in the tab and stops at the first failed step.
+// The error is reported at the path of the failed step, together with failure artifacts if they are enabled.
+func runSteps(ctx context.Context, tabCtx context.Context, steps []recipeStep, opts runOptions) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	// The tab is attached by the first run and lives as long as the context of this run, so it's run without actions.
+	if err := chromedp.Run(tabCtx); err != nil {
+		diags.AddError("Can't open the browser tab", err.Error())
+		return diags
+	}
+
+	runCtx := tabCtx
+	if opts.timeout > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(tabCtx, opts.timeout)
+		defer cancel()
+	}
+
+	step, err := runStepList(ctx, runCtx, steps, opts)
+	if err == nil {
+		return diags
+	}
+	if errors.Is(err, context.DeadlineExceeded) && runCtx.Err() != nil {
+		err = fmt.Errorf("recipe timed out after %s: %w", opts.timeout, err)
+	}
+	// Artifacts are captured in the tab context, which outlives timeouts of the recipe and the action.
+	detail := step.redact(fmt.Sprintf("%s failed: %s", step.name, actionsErrorDetail(tabCtx, opts.artifactsDir, err)))
+	if step.path.Equal(path.Empty()) {
+		diags.AddError("can't process actions", detail)
+	} else {
+		diags.AddAttributeError(step.path, "can't process actions", detail)
+	}
+	return diags
+}
+
+// runStepList runs steps one by one and returns the first failed step with its error.
+// Steps nested in blocks run after the step of the block, once for every scope it has set.
+func runStepList(ctx context.Context, runCtx context.Context, steps []recipeStep, opts runOptions) (recipeStep, error) {
+	for i, step := range steps {
+		start := time.Now()
+		err := runStepWithRetries(ctx, runCtx, step, opts)
+		fields := map[string]interface{}{
+			"index":    i,
+			"action":   step.name,
+			"duration": time.Since(start).String(),
+		}
+		if err != nil {
+			fields["error"] = err.Error()
+			tflog.Debug(step.logContext(ctx), "action failed", fields)
+			return step, err
+		}
+		if step.block != nil {
+			fields["iterations"] = len(step.block.scopes)
+		}
+		tflog.Debug(step.logContext(ctx), "action finished", fields)
+
+		if step.block == nil {
+			continue
+		}
+		// Scopes are copied, nested runs of the same block in loops reset them.
+		for _, scope := range append([]*nodeScope(nil), step.block.scopes...) {
+			if failed, err := runStepList(ctx, withNodeScope(runCtx, scope), step.children, opts); err != nil {
+				return failed, err
+			}
+		}
+	}
+	return recipeStep{}, nil
+}
+
+// runStepWithRetries runs the step until it succeeds or attempts of its retry policy run out.
+// Failed attempts are logged with ctx, retries stop when runCtx is done.
+func runStepWithRetries(ctx context.Context, runCtx context.Context, step recipeStep, opts runOptions) error {
+	policy := opts.retry.merge(step.retry)
+	for attempt := 1; ; attempt++ {
+		err := runStep(runCtx, step, opts)
+		if err == nil || attempt >= policy.attempts || runCtx.Err() != nil || !policy.retries(err) {
+			return err
+		}
+
+		delay := policy.delay(attempt + 1)
+		tflog.Info(step.logContext(ctx), "action attempt failed, retrying", map[string]interface{}{
+			"action":      step.name,
+			"attempt":     attempt,
+			"attempts":    policy.attempts,
+			"error":       err.Error(),
+			"error_class": errorClass(err),
+			"delay":       delay.String(),
+		})
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-runCtx.Done():
+			timer.Stop()
+			return err
+		}
+	}
+}
+
+// runStep runs the step within its timeout.
+func runStep(ctx context.Context, step recipeStep, opts runOptions) error {
+	timeout := opts.actionTimeout
+	if step.timeout > 0 {
+		timeout = step.timeout
+	}
+	if timeout <= 0 {
+		return chromedp.Run(ctx, step.action)
+	}
+
+	actionCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	err := chromedp.Run(actionCtx, step.action)
+	if err != nil && errors.Is(actionCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+		return fmt.Errorf("action timed out after %s: %w", timeout, err)
+	}
+	return err
+}
@@ -0,0 +1,116 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chromedp/chromedp"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActionDefinition_describe(t *testing.T) {
+	def := actionDefinition{
+		args: testArgs("evaluate", strings.Repeat("x", 100)),
+		path: path.Root("actions").AtListIndex(2),
+	}
+	assert.Equal(t, `actions[2]: evaluate "`+strings.Repeat("x", maxDescribedArgLength)+`..."`, def.describe())
+
+	def = actionDefinition{
+		args: testArgs("send_keys", "input#password", "hunter2"),
+		path: path.Root("actions").AtListIndex(3),
+	}
+	assert.Equal(t, `actions[3]: send_keys "input#password" (sensitive)`, def.describe())
+	assert.Equal(t, []string{"send_keys", "input#password", "(sensitive)"}, def.redactedArgs())
+	assert.Equal(t, []string{"hunter2"}, def.secrets())
+
+	def = actionDefinition{
+		args: testArgs("evaluate", "fetch('/token?key=secret')", "token", "await", "sensitive"),
+		path: path.Root("actions").AtListIndex(4),
+	}
+	assert.Equal(t, `actions[4]: evaluate (sensitive) (sensitive) (sensitive) (sensitive)`, def.describe())
+	assert.Equal(t, []string{"fetch('/token?key=secret')", "token", "await", "sensitive"}, def.secrets())
+
+	def = actionDefinition{args: testArgs("set_value", "input#comment", "")}
+	assert.Empty(t, def.secrets())
+
+	// Options of wrong actions are unknown, so "sensitive" hides all their arguments.
+	def = actionDefinition{args: testArgs("text", "#api-token", "token", "extra", "sensitive")}
+	assert.Equal(t, []string{"text", "(sensitive)", "(sensitive)", "(sensitive)", "(sensitive)"}, def.redactedArgs())
+}
+
+func TestRecipeStep_logContext(t *testing.T) {
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+
+	step := recipeStep{secrets: []string{"hunter2"}}
+	tflog.Debug(step.logContext(ctx), "action failed", map[string]interface{}{"error": "wrong password hunter2"})
+
+	assert.Contains(t, output.String(), "wrong password ***")
+	assert.NotContains(t, output.String(), "hunter2")
+
+	output.Reset()
+	step.placedSecrets = &[]string{"s3cr3t"}
+	tflog.Debug(step.logContext(ctx), "action failed", map[string]interface{}{"error": "can't navigate to https://example.com/?token=s3cr3t"})
+	assert.Contains(t, output.String(), "token=***")
+	assert.NotContains(t, output.String(), "s3cr3t")
+}
+
+func TestRecipeStep_redact(t *testing.T) {
+	step := recipeStep{secrets: []string{"hunter2"}, placedSecrets: &[]string{"s3cr3t"}}
+	assert.Equal(t, "navigate (sensitive) failed: wrong token (sensitive)", step.redact("navigate hunter2 failed: wrong token s3cr3t"))
+	assert.Equal(t, "no secrets", recipeStep{}.redact("no secrets"))
+}
+
+func TestBuildActions(t *testing.T) {
+	defs := []actionDefinition{
+		{args: testArgs("navigate", "about:blank"), path: path.Root("actions").AtListIndex(0), positional: true},
+		{args: testArgs("wait_visible"), path: path.Root("actions").AtListIndex(1), positional: true},
+		{args: testArgs("text", "h1", "title"), path: path.Root("actions").AtListIndex(2), positional: true},
+	}
+	steps, diags := buildActions(context.Background(), defs, map[string]interface{}{})
+	assert.Len(t, steps, 2)
+	assert.Equal(t, `actions[2]: text "h1" "title"`, steps[1].name)
+	assert.True(t, steps[1].path.Equal(path.Root("actions").AtListIndex(2)))
+	if assert.Equal(t, 1, diags.ErrorsCount()) {
+		assert.Contains(t, diags[0].Summary(), "wrong action definition")
+	}
+}
+
+func TestRunSteps_invalidTab(t *testing.T) {
+	diags := runSteps(context.Background(), context.Background(), nil, runOptions{})
+	if assert.Equal(t, 1, diags.ErrorsCount()) {
+		assert.Equal(t, "Can't open the browser tab", diags[0].Summary())
+	}
+}
+
+func TestProviderData_runOptions(t *testing.T) {
+	data := &providerData{defaultActionTimeout: time.Minute}
+
+	opts, diags := data.runOptions(types.StringValue("5m"), types.StringValue("failures"), &RetryModel{Attempts: types.Int64Value(3), Backoff: types.StringNull()})
+	assert.False(t, diags.HasError())
+	assert.Equal(t, runOptions{timeout: 5 * time.Minute, actionTimeout: time.Minute, artifactsDir: "failures", retry: retryPolicy{attempts: 3}}, opts)
+
+	opts, diags = data.runOptions(types.StringNull(), types.StringNull(), nil)
+	assert.False(t, diags.HasError())
+	assert.Equal(t, runOptions{actionTimeout: time.Minute}, opts)
+
+	_, diags = data.runOptions(types.StringValue("forever"), types.StringNull(), nil)
+	assert.True(t, diags.HasError())
+}
+
+func TestPlaceValues(t *testing.T) {
+	id := "42"
+	values := map[string]interface{}{}
+	action := placeValues(chromedp.Sleep(time.Millisecond), map[string]interface{}{"id": &id}, values)
+	assert.Empty(t, values)
+
+	assert.NoError(t, action.Do(context.Background()))
+	assert.Same(t, &id, values["id"])
+}
@@ -0,0 +1,201 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/chromedp"
+)
+
+// screenshotOptions describes the area and the image format of the screenshot.
+// Only one of selector, fullPage and clip can be set, the screenshot of the viewport is made otherwise.
+type screenshotOptions struct {
+	selector string
+	fullPage bool
+	clip     *page.Viewport
+	// format defaults to png.
+	format page.CaptureScreenshotFormat
+	// quality is the compression quality of jpeg and webp formats, 0 means the browser default.
+	quality int64
+	// scale is the device scale factor of the screenshot, 0 means 1.
+	scale float64
+}
+
+// parseScreenshotFormat parses the image format of the screenshot: "png", "jpeg" or "webp".
+func parseScreenshotFormat(format string) (page.CaptureScreenshotFormat, error) {
+	switch f := page.CaptureScreenshotFormat(format); f {
+	case page.CaptureScreenshotFormatPng, page.CaptureScreenshotFormatJpeg, page.CaptureScreenshotFormatWebp:
+		return f, nil
+	}
+	return "", fmt.Errorf(`screenshot format must be "png", "jpeg" or "webp", got %q`, format)
+}
+
+// hasQuality reports whether the image format has the compression quality, only "jpeg" and "webp" have it.
+func hasQuality(format page.CaptureScreenshotFormat) bool {
+	return format == page.CaptureScreenshotFormatJpeg || format == page.CaptureScreenshotFormatWebp
+}
+
+// parse parses option of the screenshot action. It returns false if opt isn't a screenshot option.
+func (o *screenshotOptions) parse(opt string) (bool, error) {
+	if opt == "full_page" {
+		o.fullPage = true
+		return true, nil
+	}
+
+	key, value, found := strings.Cut(opt, "=")
+	if !found {
+		return false, nil
+	}
+
+	switch key {
+	case "format":
+		format, err := parseScreenshotFormat(value)
+		if err != nil {
+			return true, err
+		}
+		o.format = format
+	case "quality":
+		quality, err := strconv.ParseInt(value, 10, 64)
+		// 0 isn't accepted, it means the browser default.
+		if err != nil || quality < 1 || quality > 100 {
+			return true, fmt.Errorf("quality screenshot option must be integer from 1 to 100, got %q", value)
+		}
+		o.quality = quality
+	case "scale":
+		scale, err := strconv.ParseFloat(value, 64)
+		if err != nil || scale <= 0 {
+			return true, fmt.Errorf("scale screenshot option must be positive number, got %q", value)
+		}
+		o.scale = scale
+	default:
+		return false, nil
+	}
+	return true, nil
+}
+
+// params returns parameters of the screenshot except the clip.
+func (o screenshotOptions) params() *page.CaptureScreenshotParams {
+	params := page.CaptureScreenshot().WithFromSurface(true)
+	if o.format != "" {
+		params = params.WithFormat(o.format)
+	}
+	if o.quality != 0 && hasQuality(o.format) {
+		params = params.WithQuality(o.quality)
+	}
+	return params
+}
+
+// scaleOrDefault returns the scale of clipped screenshots.
+func (o screenshotOptions) scaleOrDefault() float64 {
+	if o.scale == 0 {
+		return 1
+	}
+	return o.scale
+}
+
+// clientRectJS returns the box of the element relative to the document, as chromedp.Screenshot does.
+const clientRectJS = `function() {
+	const e = this.getBoundingClientRect(), t = this.ownerDocument.documentElement.getBoundingClientRect();
+	return {x: e.left - t.left, y: e.top - t.top, width: e.width, height: e.height};
+}`
+
+// screenshot makes the screenshot and places the image into res.
+func screenshot(opts screenshotOptions, res *[]byte) chromedp.Action {
+	if opts.selector != "" {
+		return chromedp.QueryAfter(opts.selector, func(ctx context.Context, execCtx runtime.ExecutionContextID, nodes ...*cdp.Node) error {
+			if len(nodes) < 1 {
+				return fmt.Errorf("selector %q did not return any nodes", opts.selector)
+			}
+			var clip page.Viewport
+			if err := callNodeFunction(ctx, nodes[0], clientRectJS, &clip); err != nil {
+				return err
+			}
+			// Fractional dimensions are not handled well by the browser, so the box is aligned to pixels.
+			x, y := math.Round(clip.X), math.Round(clip.Y)
+			clip.Width, clip.Height = math.Round(clip.Width+clip.X-x), math.Round(clip.Height+clip.Y-y)
+			clip.X, clip.Y = x, y
+			clip.Scale = opts.scaleOrDefault()
+
+			buf, err := opts.params().WithCaptureBeyondViewport(true).WithClip(&clip).Do(ctx)
+			if err != nil {
+				return err
+			}
+			*res = buf
+			return nil
+		}, chromedp.NodeVisible)
+	}
+
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		params := opts.params()
+		switch {
+		case opts.fullPage:
+			_, _, _, _, _, contentSize, err := page.GetLayoutMetrics().Do(ctx)
+			if err != nil {
+				return err
+			}
+			params = params.WithCaptureBeyondViewport(true).WithClip(&page.Viewport{
+				Width:  math.Ceil(contentSize.Width),
+				Height: math.Ceil(contentSize.Height),
+				Scale:  opts.scaleOrDefault(),
+			})
+		case opts.clip != nil:
+			clip := *opts.clip
+			clip.Scale = opts.scaleOrDefault()
+			params = params.WithCaptureBeyondViewport(true).WithClip(&clip)
+		case opts.scale != 0:
+			// The scale is applied to clipped screenshots only, so the viewport is clipped explicitly.
+			_, _, _, _, viewport, _, err := page.GetLayoutMetrics().Do(ctx)
+			if err != nil {
+				return err
+			}
+			params = params.WithClip(&page.Viewport{
+				X:      viewport.PageX,
+				Y:      viewport.PageY,
+				Width:  viewport.ClientWidth,
+				Height: viewport.ClientHeight,
+				Scale:  opts.scale,
+			})
+		}
+
+		buf, err := params.Do(ctx)
+		if err != nil {
+			return err
+		}
+		*res = buf
+		return nil
+	})
+}
+
+// createScreenshotDir creates the directory of the screenshot file.
+func createScreenshotDir(filename string) error {
+	dir := filepath.Dir(filename)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("can't create directory for the screenshot %s: %w", dir, err)
+	}
+	return nil
+}
+
+// saveScreenshot makes the screenshot and writes it into the file.
+func saveScreenshot(filename string, opts screenshotOptions) chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		var buf []byte
+		if err := screenshot(opts, &buf).Do(ctx); err != nil {
+			return err
+		}
+		if err := createScreenshotDir(filename); err != nil {
+			return err
+		}
+		if err := os.WriteFile(filename, buf, 0600); err != nil {
+			return fmt.Errorf("can't save the screenshot: %w", err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,130 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/chromedp/cdproto/page"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecipeDataSourceModel_screenshotOptions(t *testing.T) {
+	opts, err := RecipeDataSourceModel{
+		ScreenshotFormat:  types.StringValue("jpeg"),
+		ScreenshotQuality: types.Int64Value(80),
+		ScreenshotClip: &ClipModel{
+			X:      types.Float64Value(10),
+			Y:      types.Float64Value(20),
+			Width:  types.Float64Value(300),
+			Height: types.Float64Value(200),
+		},
+		ScreenshotScale: types.Float64Value(2),
+	}.screenshotOptions()
+	assert.NoError(t, err)
+	assert.Equal(t, screenshotOptions{
+		format:  page.CaptureScreenshotFormatJpeg,
+		quality: 80,
+		clip:    &page.Viewport{X: 10, Y: 20, Width: 300, Height: 200},
+		scale:   2,
+	}, opts)
+	assert.Equal(t, int64(80), opts.params().Quality)
+
+	opts, err = RecipeDataSourceModel{
+		ScreenshotSelector: types.StringValue("#app"),
+		ScreenshotFormat:   types.StringNull(),
+	}.screenshotOptions()
+	assert.NoError(t, err)
+	assert.Equal(t, screenshotOptions{selector: "#app"}, opts)
+	assert.Equal(t, 1.0, opts.scaleOrDefault())
+
+	_, err = RecipeDataSourceModel{ScreenshotFormat: types.StringValue("gif")}.screenshotOptions()
+	assert.Error(t, err)
+}
+
+func TestScreenshotOptions_params(t *testing.T) {
+	params := screenshotOptions{format: page.CaptureScreenshotFormatPng, quality: 50}.params()
+	assert.Equal(t, page.CaptureScreenshotFormatPng, params.Format)
+	assert.Zero(t, params.Quality)
+
+	params = screenshotOptions{format: page.CaptureScreenshotFormatWebp, quality: 50}.params()
+	assert.Equal(t, int64(50), params.Quality)
+
+	params = screenshotOptions{}.params()
+	assert.Empty(t, params.Format)
+}
+
+func TestScreenshotOptions_parse(t *testing.T) {
+	var opts screenshotOptions
+	for _, opt := range []string{"full_page", "format=webp", "quality=70", "scale=1.5"} {
+		ok, err := opts.parse(opt)
+		assert.True(t, ok, opt)
+		assert.NoError(t, err, opt)
+	}
+	assert.Equal(t, screenshotOptions{fullPage: true, format: page.CaptureScreenshotFormatWebp, quality: 70, scale: 1.5}, opts)
+
+	for _, opt := range []string{"#app", "input[name=q]"} {
+		ok, err := opts.parse(opt)
+		assert.False(t, ok, opt)
+		assert.NoError(t, err, opt)
+	}
+
+	for _, opt := range []string{"format=gif", "quality=0", "quality=101", "scale=0", "scale=big"} {
+		ok, err := opts.parse(opt)
+		assert.True(t, ok, opt)
+		assert.Error(t, err, opt)
+	}
+}
+
+func TestActionBuilderScreenshot(t *testing.T) {
+	for _, args := range [][]string{
+		{"screenshot", "login.png"},
+		{"screenshot", "login.png", "input[name=q]"},
+		{"screenshot", "login.jpeg", "form", "format=jpeg", "quality=90"},
+		{"screenshot", "page.png", "full_page", "scale=2"},
+	} {
+		_, err := actionBuilder(testArgs(args...))
+		assert.NoError(t, err, args)
+	}
+
+	for _, args := range [][]string{
+		{"screenshot"},
+		{"screenshot", "page.png", "form", "full_page"},
+		{"screenshot", "page.png", "format=jpeg", "form"},
+		{"screenshot", "page.png", "format=bmp"},
+		{"screenshot", "page.png", "quality=80"},
+		{"screenshot", "page.png", "format=png", "quality=80"},
+	} {
+		_, err := actionBuilder(testArgs(args...))
+		assert.Error(t, err, args)
+	}
+
+	args, err := StepModel{
+		Screenshot: &ScreenshotStepModel{
+			Filename: types.StringValue("login.jpeg"),
+			Selector: types.StringValue("form"),
+			FullPage: types.BoolNull(),
+			Format:   types.StringValue("jpeg"),
+			Quality:  types.Int64Value(90),
+			Scale:    types.Float64Value(1.5),
+		},
+	}.actionArgs()
+	assert.NoError(t, err)
+	assert.Equal(t, testArgs("screenshot", "login.jpeg", "form", "format=jpeg", "quality=90", "scale=1.5"), args)
+	_, err = actionBuilder(args)
+	assert.NoError(t, err)
+
+	// The format may be set by the unknown value, so the quality isn't rejected during config validation.
+	args, err = StepModel{
+		Screenshot: &ScreenshotStepModel{
+			Filename: types.StringValue("login.jpeg"),
+			Selector: types.StringNull(),
+			FullPage: types.BoolNull(),
+			Format:   types.StringUnknown(),
+			Quality:  types.Int64Value(90),
+			Scale:    types.Float64Null(),
+		},
+	}.actionArgs()
+	assert.NoError(t, err)
+	_, err = actionBuilder(args)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,198 @@
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/fetch"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/cdproto/storage"
+	"github.com/chromedp/chromedp"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// sessionState is the browser session exported by chromedp_session data source:
+// cookies of the browser context and storage of the page origin.
+type sessionState struct {
+	Cookies []*network.Cookie `json:"cookies"`
+	Origins []originStorage   `json:"origins"`
+}
+
+// originStorage holds localStorage and sessionStorage items of the origin.
+type originStorage struct {
+	Origin         string            `json:"origin"`
+	LocalStorage   map[string]string `json:"local_storage"`
+	SessionStorage map[string]string `json:"session_storage"`
+}
+
+// parseSession parses the session exported by chromedp_session data source.
+func parseSession(s string) (sessionState, error) {
+	var state sessionState
+	if err := json.Unmarshal([]byte(s), &state); err != nil {
+		return sessionState{}, fmt.Errorf("session must be JSON exported by chromedp_session data source: %w", err)
+	}
+	for _, origin := range state.Origins {
+		if origin.Origin == "" {
+			return sessionState{}, fmt.Errorf("session storage must have origin")
+		}
+	}
+	return state, nil
+}
+
+// cookieParams converts exported cookies into parameters to set them.
+func (s sessionState) cookieParams() []*network.CookieParam {
+	params := make([]*network.CookieParam, 0, len(s.Cookies))
+	for _, cookie := range s.Cookies {
+		param := &network.CookieParam{
+			Name:         cookie.Name,
+			Value:        cookie.Value,
+			Domain:       cookie.Domain,
+			Path:         cookie.Path,
+			Secure:       cookie.Secure,
+			HTTPOnly:     cookie.HTTPOnly,
+			SameSite:     cookie.SameSite,
+			Priority:     cookie.Priority,
+			SameParty:    cookie.SameParty,
+			SourceScheme: cookie.SourceScheme,
+			SourcePort:   cookie.SourcePort,
+			PartitionKey: cookie.PartitionKey,
+		}
+		if !cookie.Session {
+			expires := cdp.TimeSinceEpoch(time.Unix(0, int64(cookie.Expires*float64(time.Second))))
+			param.Expires = &expires
+		}
+		params = append(params, param)
+	}
+	return params
+}
+
+// originStorageJS returns storage items of the page origin. Pages without origin like "about:blank" give empty origin.
+const originStorageJS = `(() => {
+	const items = (storage) => Object.fromEntries(Object.keys(storage).map((key) => [key, storage.getItem(key)]));
+	try {
+		if (location.origin === "null") {
+			return {origin: ""};
+		}
+		return {origin: location.origin, local_storage: items(localStorage), session_storage: items(sessionStorage)};
+	} catch (e) {
+		return {origin: ""};
+	}
+})()`
+
+// restoreStorageJS sets storage items of the page origin, %s is replaced with JSON-encoded originStorage.
+const restoreStorageJS = `((state) => {
+	for (const [key, value] of Object.entries(state.local_storage || {})) {
+		localStorage.setItem(key, value);
+	}
+	for (const [key, value] of Object.entries(state.session_storage || {})) {
+		sessionStorage.setItem(key, value);
+	}
+})(%s)`
+
+// blankPage is served in place of real pages while storage is restored.
+var blankPage = base64.StdEncoding.EncodeToString([]byte("<!DOCTYPE html><html><head></head><body></body></html>"))
+
+// browserExecutor returns ctx running commands in the browser, and the browser context of the tab.
+func browserExecutor(ctx context.Context) (context.Context, cdp.BrowserContextID) {
+	c := chromedp.FromContext(ctx)
+	return cdp.WithExecutor(ctx, c.Browser), c.BrowserContextID
+}
+
+// captureSession gets all cookies of the tab's browser context and storage of the current page origin.
+func captureSession(res *sessionState) chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		browserCtx, browserContextID := browserExecutor(ctx)
+		cookies, err := storage.GetCookies().WithBrowserContextID(browserContextID).Do(browserCtx)
+		if err != nil {
+			return fmt.Errorf("can't get cookies: %w", err)
+		}
+
+		var origin originStorage
+		if err := chromedp.Evaluate(originStorageJS, &origin).Do(ctx); err != nil {
+			return fmt.Errorf("can't get storage: %w", err)
+		}
+
+		state := sessionState{Cookies: cookies, Origins: []originStorage{}}
+		if state.Cookies == nil {
+			state.Cookies = []*network.Cookie{}
+		}
+		if origin.Origin != "" {
+			state.Origins = append(state.Origins, origin)
+		}
+		*res = state
+		return nil
+	})
+}
+
+// restoreSession sets cookies and storage of the session in the tab.
+// Storage is bound to the page origin, so every origin is opened with the blank page served in place of the real one,
+// the tab is left at "about:blank" afterwards.
+func restoreSession(state sessionState) chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		if len(state.Cookies) > 0 {
+			browserCtx, browserContextID := browserExecutor(ctx)
+			err := storage.SetCookies(state.cookieParams()).WithBrowserContextID(browserContextID).Do(browserCtx)
+			if err != nil {
+				return fmt.Errorf("can't set cookies: %w", err)
+			}
+		}
+		if len(state.Origins) == 0 {
+			return nil
+		}
+
+		chromedp.ListenTarget(ctx, func(ev interface{}) {
+			paused, ok := ev.(*fetch.EventRequestPaused)
+			if !ok {
+				return
+			}
+			// Commands can't be sent from the listener, it blocks events of the tab.
+			go func() {
+				_ = chromedp.Run(ctx, fetch.FulfillRequest(paused.RequestID, 200).
+					WithResponseHeaders([]*fetch.HeaderEntry{{Name: "Content-Type", Value: "text/html"}}).
+					WithBody(blankPage))
+			}()
+		})
+		patterns := []*fetch.RequestPattern{{URLPattern: "*", ResourceType: network.ResourceTypeDocument}}
+		if err := fetch.Enable().WithPatterns(patterns).Do(ctx); err != nil {
+			return err
+		}
+
+		for _, origin := range state.Origins {
+			encoded, err := json.Marshal(origin)
+			if err != nil {
+				return err
+			}
+			if err := chromedp.Navigate(origin.Origin).Do(ctx); err != nil {
+				return fmt.Errorf("can't open %s to restore storage: %w", origin.Origin, err)
+			}
+			if err := chromedp.Evaluate(fmt.Sprintf(restoreStorageJS, encoded), nil).Do(ctx); err != nil {
+				return fmt.Errorf("can't restore storage of %s: %w", origin.Origin, err)
+			}
+		}
+
+		if err := fetch.Disable().Do(ctx); err != nil {
+			return err
+		}
+		return chromedp.Navigate("about:blank").Do(ctx)
+	})
+}
+
+// restoreSessionSteps returns the step restoring the session given in "session" attribute, if it's set.
+func restoreSessionSteps(session types.String) ([]recipeStep, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	if session.ValueString() == "" {
+		return nil, diags
+	}
+	state, err := parseSession(session.ValueString())
+	if err != nil {
+		diags.AddAttributeError(path.Root("session"), "wrong session", err.Error())
+		return nil, diags
+	}
+	return []recipeStep{{action: restoreSession(state), name: "restoring session", path: path.Root("session")}}, diags
+}
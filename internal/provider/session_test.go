@@ -0,0 +1,44 @@
+package provider
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/chromedp/cdproto/network"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSession(t *testing.T) {
+	state := sessionState{
+		Cookies: []*network.Cookie{
+			{Name: "token", Value: "secret", Domain: "example.com", Path: "/", Expires: 1893456000, Secure: true, HTTPOnly: true, SameSite: network.CookieSameSiteLax, Priority: network.CookiePriorityMedium, SourceScheme: network.CookieSourceSchemeSecure, SourcePort: 443},
+			{Name: "visit", Value: "1", Domain: "example.com", Path: "/", Expires: -1, Session: true, Priority: network.CookiePriorityMedium, SourceScheme: network.CookieSourceSchemeSecure, SourcePort: 443},
+		},
+		Origins: []originStorage{
+			{Origin: "https://example.com", LocalStorage: map[string]string{"theme": "dark"}, SessionStorage: map[string]string{}},
+		},
+	}
+	encoded, err := json.Marshal(state)
+	assert.NoError(t, err)
+
+	parsed, err := parseSession(string(encoded))
+	assert.NoError(t, err)
+	assert.Equal(t, state, parsed)
+
+	params := parsed.cookieParams()
+	if !assert.Len(t, params, 2) {
+		return
+	}
+	assert.Equal(t, "token", params[0].Name)
+	assert.Equal(t, network.CookieSameSiteLax, params[0].SameSite)
+	if assert.NotNil(t, params[0].Expires) {
+		assert.Equal(t, time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC), params[0].Expires.Time().UTC())
+	}
+	assert.Nil(t, params[1].Expires)
+
+	_, err = parseSession("cookies")
+	assert.Error(t, err)
+	_, err = parseSession(`{"origins": [{"local_storage": {"theme": "dark"}}]}`)
+	assert.Error(t, err)
+}
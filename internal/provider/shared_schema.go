@@ -0,0 +1,252 @@
+package provider
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	ephemeralschema "github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	resourceschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+)
+
+// Attributes shared by data sources, resources and ephemeral resources, like **steps** and **retry**, are defined once
+// in data source schema and converted into schemas of resources and ephemeral resources.
+// Converters panic on kinds of attributes which aren't used by shared attributes,
+// TestSharedAttributes converts all attributes of data sources, so such attributes fail tests.
+
+// ephemeralAttribute converts the attribute of data source schema into the attribute of ephemeral resource schema.
+func ephemeralAttribute(attribute schema.Attribute) ephemeralschema.Attribute {
+	switch a := attribute.(type) {
+	case schema.StringAttribute:
+		return ephemeralschema.StringAttribute{
+			CustomType:          a.CustomType,
+			Required:            a.Required,
+			Optional:            a.Optional,
+			Computed:            a.Computed,
+			Sensitive:           a.Sensitive,
+			Description:         a.Description,
+			MarkdownDescription: a.MarkdownDescription,
+			DeprecationMessage:  a.DeprecationMessage,
+			Validators:          a.Validators,
+		}
+	case schema.BoolAttribute:
+		return ephemeralschema.BoolAttribute{
+			CustomType:          a.CustomType,
+			Required:            a.Required,
+			Optional:            a.Optional,
+			Computed:            a.Computed,
+			Sensitive:           a.Sensitive,
+			Description:         a.Description,
+			MarkdownDescription: a.MarkdownDescription,
+			DeprecationMessage:  a.DeprecationMessage,
+			Validators:          a.Validators,
+		}
+	case schema.Int64Attribute:
+		return ephemeralschema.Int64Attribute{
+			CustomType:          a.CustomType,
+			Required:            a.Required,
+			Optional:            a.Optional,
+			Computed:            a.Computed,
+			Sensitive:           a.Sensitive,
+			Description:         a.Description,
+			MarkdownDescription: a.MarkdownDescription,
+			DeprecationMessage:  a.DeprecationMessage,
+			Validators:          a.Validators,
+		}
+	case schema.Float64Attribute:
+		return ephemeralschema.Float64Attribute{
+			CustomType:          a.CustomType,
+			Required:            a.Required,
+			Optional:            a.Optional,
+			Computed:            a.Computed,
+			Sensitive:           a.Sensitive,
+			Description:         a.Description,
+			MarkdownDescription: a.MarkdownDescription,
+			DeprecationMessage:  a.DeprecationMessage,
+			Validators:          a.Validators,
+		}
+	case schema.ListAttribute:
+		return ephemeralschema.ListAttribute{
+			ElementType:         a.ElementType,
+			CustomType:          a.CustomType,
+			Required:            a.Required,
+			Optional:            a.Optional,
+			Computed:            a.Computed,
+			Sensitive:           a.Sensitive,
+			Description:         a.Description,
+			MarkdownDescription: a.MarkdownDescription,
+			DeprecationMessage:  a.DeprecationMessage,
+			Validators:          a.Validators,
+		}
+	case schema.MapAttribute:
+		return ephemeralschema.MapAttribute{
+			ElementType:         a.ElementType,
+			CustomType:          a.CustomType,
+			Required:            a.Required,
+			Optional:            a.Optional,
+			Computed:            a.Computed,
+			Sensitive:           a.Sensitive,
+			Description:         a.Description,
+			MarkdownDescription: a.MarkdownDescription,
+			DeprecationMessage:  a.DeprecationMessage,
+			Validators:          a.Validators,
+		}
+	case schema.SingleNestedAttribute:
+		return ephemeralschema.SingleNestedAttribute{
+			Attributes:          ephemeralAttributes(a.Attributes),
+			CustomType:          a.CustomType,
+			Required:            a.Required,
+			Optional:            a.Optional,
+			Computed:            a.Computed,
+			Sensitive:           a.Sensitive,
+			Description:         a.Description,
+			MarkdownDescription: a.MarkdownDescription,
+			DeprecationMessage:  a.DeprecationMessage,
+			Validators:          a.Validators,
+		}
+	case schema.ListNestedAttribute:
+		return ephemeralschema.ListNestedAttribute{
+			NestedObject: ephemeralschema.NestedAttributeObject{
+				Attributes: ephemeralAttributes(a.NestedObject.Attributes),
+				CustomType: a.NestedObject.CustomType,
+				Validators: a.NestedObject.Validators,
+			},
+			CustomType:          a.CustomType,
+			Required:            a.Required,
+			Optional:            a.Optional,
+			Computed:            a.Computed,
+			Sensitive:           a.Sensitive,
+			Description:         a.Description,
+			MarkdownDescription: a.MarkdownDescription,
+			DeprecationMessage:  a.DeprecationMessage,
+			Validators:          a.Validators,
+		}
+	}
+	panic(fmt.Sprintf("unsupported attribute of ephemeral resource: %T", attribute))
+}
+
+func ephemeralAttributes(attributes map[string]schema.Attribute) map[string]ephemeralschema.Attribute {
+	result := make(map[string]ephemeralschema.Attribute, len(attributes))
+	for name, attribute := range attributes {
+		result[name] = ephemeralAttribute(attribute)
+	}
+	return result
+}
+
+// resourceAttribute converts the attribute of data source schema into the attribute of resource schema.
+func resourceAttribute(attribute schema.Attribute) resourceschema.Attribute {
+	switch a := attribute.(type) {
+	case schema.StringAttribute:
+		return resourceschema.StringAttribute{
+			CustomType:          a.CustomType,
+			Required:            a.Required,
+			Optional:            a.Optional,
+			Computed:            a.Computed,
+			Sensitive:           a.Sensitive,
+			Description:         a.Description,
+			MarkdownDescription: a.MarkdownDescription,
+			DeprecationMessage:  a.DeprecationMessage,
+			Validators:          a.Validators,
+		}
+	case schema.BoolAttribute:
+		return resourceschema.BoolAttribute{
+			CustomType:          a.CustomType,
+			Required:            a.Required,
+			Optional:            a.Optional,
+			Computed:            a.Computed,
+			Sensitive:           a.Sensitive,
+			Description:         a.Description,
+			MarkdownDescription: a.MarkdownDescription,
+			DeprecationMessage:  a.DeprecationMessage,
+			Validators:          a.Validators,
+		}
+	case schema.Int64Attribute:
+		return resourceschema.Int64Attribute{
+			CustomType:          a.CustomType,
+			Required:            a.Required,
+			Optional:            a.Optional,
+			Computed:            a.Computed,
+			Sensitive:           a.Sensitive,
+			Description:         a.Description,
+			MarkdownDescription: a.MarkdownDescription,
+			DeprecationMessage:  a.DeprecationMessage,
+			Validators:          a.Validators,
+		}
+	case schema.Float64Attribute:
+		return resourceschema.Float64Attribute{
+			CustomType:          a.CustomType,
+			Required:            a.Required,
+			Optional:            a.Optional,
+			Computed:            a.Computed,
+			Sensitive:           a.Sensitive,
+			Description:         a.Description,
+			MarkdownDescription: a.MarkdownDescription,
+			DeprecationMessage:  a.DeprecationMessage,
+			Validators:          a.Validators,
+		}
+	case schema.ListAttribute:
+		return resourceschema.ListAttribute{
+			ElementType:         a.ElementType,
+			CustomType:          a.CustomType,
+			Required:            a.Required,
+			Optional:            a.Optional,
+			Computed:            a.Computed,
+			Sensitive:           a.Sensitive,
+			Description:         a.Description,
+			MarkdownDescription: a.MarkdownDescription,
+			DeprecationMessage:  a.DeprecationMessage,
+			Validators:          a.Validators,
+		}
+	case schema.MapAttribute:
+		return resourceschema.MapAttribute{
+			ElementType:         a.ElementType,
+			CustomType:          a.CustomType,
+			Required:            a.Required,
+			Optional:            a.Optional,
+			Computed:            a.Computed,
+			Sensitive:           a.Sensitive,
+			Description:         a.Description,
+			MarkdownDescription: a.MarkdownDescription,
+			DeprecationMessage:  a.DeprecationMessage,
+			Validators:          a.Validators,
+		}
+	case schema.SingleNestedAttribute:
+		return resourceschema.SingleNestedAttribute{
+			Attributes:          resourceAttributes(a.Attributes),
+			CustomType:          a.CustomType,
+			Required:            a.Required,
+			Optional:            a.Optional,
+			Computed:            a.Computed,
+			Sensitive:           a.Sensitive,
+			Description:         a.Description,
+			MarkdownDescription: a.MarkdownDescription,
+			DeprecationMessage:  a.DeprecationMessage,
+			Validators:          a.Validators,
+		}
+	case schema.ListNestedAttribute:
+		return resourceschema.ListNestedAttribute{
+			NestedObject: resourceschema.NestedAttributeObject{
+				Attributes: resourceAttributes(a.NestedObject.Attributes),
+				CustomType: a.NestedObject.CustomType,
+				Validators: a.NestedObject.Validators,
+			},
+			CustomType:          a.CustomType,
+			Required:            a.Required,
+			Optional:            a.Optional,
+			Computed:            a.Computed,
+			Sensitive:           a.Sensitive,
+			Description:         a.Description,
+			MarkdownDescription: a.MarkdownDescription,
+			DeprecationMessage:  a.DeprecationMessage,
+			Validators:          a.Validators,
+		}
+	}
+	panic(fmt.Sprintf("unsupported attribute of resource: %T", attribute))
+}
+
+func resourceAttributes(attributes map[string]schema.Attribute) map[string]resourceschema.Attribute {
+	result := make(map[string]resourceschema.Attribute, len(attributes))
+	for name, attribute := range attributes {
+		result[name] = resourceAttribute(attribute)
+	}
+	return result
+}
@@ -0,0 +1,59 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	ephemeralschema "github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	resourceschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSharedAttributes converts all attributes of data sources, so attributes which can't be shared fail the test
+// instead of the provider at runtime.
+func TestSharedAttributes(t *testing.T) {
+	for _, newDataSource := range []func() datasource.DataSource{NewRecipeDataSource, NewSessionDataSource} {
+		var resp datasource.SchemaResponse
+		newDataSource().Schema(context.Background(), datasource.SchemaRequest{}, &resp)
+		for name, attribute := range resp.Schema.Attributes {
+			assert.NotPanics(t, func() { ephemeralAttribute(attribute) }, name)
+			assert.NotPanics(t, func() { resourceAttribute(attribute) }, name)
+		}
+	}
+}
+
+func TestEphemeralAttribute(t *testing.T) {
+	steps, ok := ephemeralAttribute(stepsAttribute()).(ephemeralschema.ListNestedAttribute)
+	if !assert.True(t, ok) {
+		return
+	}
+	assert.Equal(t, stepsAttribute().MarkdownDescription, steps.MarkdownDescription)
+	assert.True(t, steps.Optional)
+	assert.Len(t, steps.NestedObject.Validators, 1)
+
+	click, ok := steps.NestedObject.Attributes["click"].(ephemeralschema.SingleNestedAttribute)
+	if assert.True(t, ok) {
+		assert.IsType(t, ephemeralschema.StringAttribute{}, click.Attributes["selector"])
+		assert.IsType(t, ephemeralschema.BoolAttribute{}, click.Attributes["wait_visible"])
+	}
+
+	assert.Panics(t, func() { ephemeralAttribute(schema.ObjectAttribute{}) })
+}
+
+func TestResourceAttribute(t *testing.T) {
+	retry, ok := resourceAttribute(retryAttribute("Retry policy.")).(resourceschema.SingleNestedAttribute)
+	if !assert.True(t, ok) {
+		return
+	}
+	assert.Equal(t, "Retry policy.", retry.MarkdownDescription)
+	assert.True(t, retry.Optional)
+	if attempts, ok := retry.Attributes["attempts"].(resourceschema.Int64Attribute); assert.True(t, ok) {
+		assert.True(t, attempts.Required)
+		assert.Len(t, attempts.Validators, 1)
+	}
+	assert.IsType(t, resourceschema.ListAttribute{}, retry.Attributes["on"])
+
+	assert.Panics(t, func() { resourceAttribute(schema.ObjectAttribute{}) })
+}
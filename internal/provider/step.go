@@ -0,0 +1,827 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/float64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// StepModel is a typed form of the action. Exactly one of the fields must be set.
+type StepModel struct {
+	Navigate    *NavigateStepModel `tfsdk:"navigate"`
+	WaitVisible *SelectorStepModel `tfsdk:"wait_visible"`
+	Click       *ClickStepModel    `tfsdk:"click"`
+	Value       *CaptureStepModel  `tfsdk:"value"`
+	Focus       *SelectorStepModel `tfsdk:"focus"`
+	Sleep       *SleepStepModel    `tfsdk:"sleep"`
+	Text        *CaptureStepModel  `tfsdk:"text"`
+	Cookie      *CookieStepModel   `tfsdk:"cookie"`
+
+	GetCookies   *GetCookiesStepModel   `tfsdk:"get_cookies"`
+	DeleteCookie *DeleteCookieStepModel `tfsdk:"delete_cookie"`
+	ClearCookies *EmptyStepModel        `tfsdk:"clear_cookies"`
+
+	SetValue   *InputStepModel    `tfsdk:"set_value"`
+	PressEnter *SelectorStepModel `tfsdk:"press_enter"`
+	SendKeys   *InputStepModel    `tfsdk:"send_keys"`
+	Evaluate   *EvaluateStepModel `tfsdk:"evaluate"`
+
+	TextAll      *CaptureStepModel   `tfsdk:"text_all"`
+	ValueAll     *CaptureStepModel   `tfsdk:"value_all"`
+	AttributeAll *AttributeStepModel `tfsdk:"attribute_all"`
+	Table        *TableStepModel     `tfsdk:"table"`
+
+	Attribute       *AttributeStepModel       `tfsdk:"attribute"`
+	Attributes      *CaptureStepModel         `tfsdk:"attributes"`
+	SetAttribute    *SetAttributeStepModel    `tfsdk:"set_attribute"`
+	RemoveAttribute *RemoveAttributeStepModel `tfsdk:"remove_attribute"`
+
+	Screenshot *ScreenshotStepModel `tfsdk:"screenshot"`
+
+	StorageGet    *StorageGetStepModel `tfsdk:"storage_get"`
+	StorageSet    *StorageSetStepModel `tfsdk:"storage_set"`
+	StorageRemove *StorageKeyStepModel `tfsdk:"storage_remove"`
+	StorageClear  *StorageStepModel    `tfsdk:"storage_clear"`
+
+	IfPresent   *SelectorStepModel `tfsdk:"if_present"`
+	IfVisible   *SelectorStepModel `tfsdk:"if_visible"`
+	ForEachNode *SelectorStepModel `tfsdk:"for_each_node"`
+	End         *EmptyStepModel    `tfsdk:"end"`
+
+	// Timeout, Retry and Sensitive aren't actions, they are common options of the step's action.
+	Timeout   types.String `tfsdk:"timeout"`
+	Retry     *RetryModel  `tfsdk:"retry"`
+	Sensitive types.Bool   `tfsdk:"sensitive"`
+}
+
+type NavigateStepModel struct {
+	URL types.String `tfsdk:"url"`
+}
+
+type SelectorStepModel struct {
+	Selector types.String `tfsdk:"selector"`
+}
+
+type ClickStepModel struct {
+	Selector    types.String `tfsdk:"selector"`
+	WaitVisible types.Bool   `tfsdk:"wait_visible"`
+}
+
+type CaptureStepModel struct {
+	Selector types.String `tfsdk:"selector"`
+	Name     types.String `tfsdk:"name"`
+}
+
+type SleepStepModel struct {
+	Duration types.String `tfsdk:"duration"`
+}
+
+type CookieStepModel struct {
+	Name     types.String `tfsdk:"name"`
+	Value    types.String `tfsdk:"value"`
+	Domain   types.String `tfsdk:"domain"`
+	Path     types.String `tfsdk:"path"`
+	URL      types.String `tfsdk:"url"`
+	Secure   types.Bool   `tfsdk:"secure"`
+	HTTPOnly types.Bool   `tfsdk:"http_only"`
+	SameSite types.String `tfsdk:"same_site"`
+	Session  types.Bool   `tfsdk:"session"`
+	Expires  types.String `tfsdk:"expires"`
+	MaxAge   types.String `tfsdk:"max_age"`
+}
+
+// args returns options of the cookie action.
+func (m CookieStepModel) args() []types.String {
+	var args []types.String
+	for key, value := range map[string]types.String{
+		"domain":    m.Domain,
+		"path":      m.Path,
+		"url":       m.URL,
+		"same_site": m.SameSite,
+		"expires":   m.Expires,
+		"max_age":   m.MaxAge,
+	} {
+		if value.ValueString() != "" {
+			args = append(args, types.StringValue(key+"="+value.ValueString()))
+		}
+	}
+	sort.Slice(args, func(i, j int) bool {
+		return args[i].ValueString() < args[j].ValueString()
+	})
+
+	if m.Secure.ValueBool() {
+		args = append(args, types.StringValue("secure"))
+	}
+	if m.HTTPOnly.ValueBool() {
+		args = append(args, types.StringValue("http_only"))
+	}
+	if m.Session.ValueBool() {
+		args = append(args, types.StringValue("session"))
+	}
+	return args
+}
+
+type GetCookiesStepModel struct {
+	Name types.String   `tfsdk:"name"`
+	URLs []types.String `tfsdk:"urls"`
+}
+
+type DeleteCookieStepModel struct {
+	Name   types.String `tfsdk:"name"`
+	Domain types.String `tfsdk:"domain"`
+	Path   types.String `tfsdk:"path"`
+	URL    types.String `tfsdk:"url"`
+}
+
+type EmptyStepModel struct{}
+
+type InputStepModel struct {
+	Selector types.String `tfsdk:"selector"`
+	Value    types.String `tfsdk:"value"`
+}
+
+type AttributeStepModel struct {
+	Selector  types.String `tfsdk:"selector"`
+	Attribute types.String `tfsdk:"attribute"`
+	Name      types.String `tfsdk:"name"`
+}
+
+type SetAttributeStepModel struct {
+	Selector  types.String `tfsdk:"selector"`
+	Attribute types.String `tfsdk:"attribute"`
+	Value     types.String `tfsdk:"value"`
+}
+
+type RemoveAttributeStepModel struct {
+	Selector  types.String `tfsdk:"selector"`
+	Attribute types.String `tfsdk:"attribute"`
+}
+
+type TableStepModel struct {
+	Selector  types.String `tfsdk:"selector"`
+	Name      types.String `tfsdk:"name"`
+	HeaderRow types.Int64  `tfsdk:"header_row"`
+	Columns   types.Map    `tfsdk:"columns"`
+}
+
+type ScreenshotStepModel struct {
+	Filename types.String  `tfsdk:"filename"`
+	Selector types.String  `tfsdk:"selector"`
+	FullPage types.Bool    `tfsdk:"full_page"`
+	Format   types.String  `tfsdk:"format"`
+	Quality  types.Int64   `tfsdk:"quality"`
+	Scale    types.Float64 `tfsdk:"scale"`
+}
+
+// args returns arguments of the screenshot action.
+func (m ScreenshotStepModel) args() []types.String {
+	args := verbArgs("screenshot", m.Filename)
+	if m.Selector.IsUnknown() || m.Selector.ValueString() != "" {
+		args = append(args, m.Selector)
+	}
+	if m.FullPage.ValueBool() {
+		args = append(args, types.StringValue("full_page"))
+	}
+	// Unknown options are passed as unknown arguments, so they pass the parsing during config validation.
+	switch {
+	case m.Format.IsUnknown():
+		args = append(args, types.StringUnknown())
+	case m.Format.ValueString() != "":
+		args = append(args, types.StringValue("format="+m.Format.ValueString()))
+	}
+	switch {
+	case m.Quality.IsUnknown():
+		args = append(args, types.StringUnknown())
+	case !m.Quality.IsNull():
+		args = append(args, types.StringValue("quality="+strconv.FormatInt(m.Quality.ValueInt64(), 10)))
+	}
+	switch {
+	case m.Scale.IsUnknown():
+		args = append(args, types.StringUnknown())
+	case !m.Scale.IsNull():
+		args = append(args, types.StringValue("scale="+strconv.FormatFloat(m.Scale.ValueFloat64(), 'f', -1, 64)))
+	}
+	return args
+}
+
+type StorageStepModel struct {
+	Storage types.String `tfsdk:"storage"`
+}
+
+type StorageKeyStepModel struct {
+	Storage types.String `tfsdk:"storage"`
+	Key     types.String `tfsdk:"key"`
+}
+
+type StorageGetStepModel struct {
+	Storage types.String `tfsdk:"storage"`
+	Key     types.String `tfsdk:"key"`
+	Name    types.String `tfsdk:"name"`
+}
+
+type StorageSetStepModel struct {
+	Storage types.String `tfsdk:"storage"`
+	Key     types.String `tfsdk:"key"`
+	Value   types.String `tfsdk:"value"`
+}
+
+type EvaluateStepModel struct {
+	Expression   types.String `tfsdk:"expression"`
+	Name         types.String `tfsdk:"name"`
+	AwaitPromise types.Bool   `tfsdk:"await_promise"`
+	JSON         types.Bool   `tfsdk:"json"`
+}
+
+// tableColumns returns renames of columns of the table step, nil for other steps.
+// They are passed to the table action apart from arguments, so header texts and names may contain any characters.
+func (s StepModel) tableColumns() map[string]string {
+	if s.Table == nil {
+		return nil
+	}
+	columns := map[string]string{}
+	for header, elem := range s.Table.Columns.Elements() {
+		name, ok := elem.(types.String)
+		if !ok || name.IsUnknown() {
+			continue
+		}
+		columns[header] = name.ValueString()
+	}
+	return columns
+}
+
+func verbArgs(verb string, args ...types.String) []types.String {
+	return append([]types.String{types.StringValue(verb)}, args...)
+}
+
+// actionArgs converts the step into the list of strings form accepted by actionBuilder.
+func (s StepModel) actionArgs() ([]types.String, error) {
+	var set [][]types.String
+
+	if s.Navigate != nil {
+		set = append(set, verbArgs("navigate", s.Navigate.URL))
+	}
+	if s.WaitVisible != nil {
+		set = append(set, verbArgs("wait_visible", s.WaitVisible.Selector))
+	}
+	if s.Click != nil {
+		args := verbArgs("click", s.Click.Selector)
+		if s.Click.WaitVisible.ValueBool() {
+			args = append(args, types.StringValue("visible"))
+		}
+		set = append(set, args)
+	}
+	if s.Value != nil {
+		set = append(set, verbArgs("value", s.Value.Selector, s.Value.Name))
+	}
+	if s.Focus != nil {
+		set = append(set, verbArgs("focus", s.Focus.Selector))
+	}
+	if s.Sleep != nil {
+		set = append(set, verbArgs("sleep", s.Sleep.Duration))
+	}
+	if s.Text != nil {
+		set = append(set, verbArgs("text", s.Text.Selector, s.Text.Name))
+	}
+	if s.Cookie != nil {
+		args := verbArgs("cookie", s.Cookie.Name, s.Cookie.Value)
+		set = append(set, append(args, s.Cookie.args()...))
+	}
+	if s.GetCookies != nil {
+		set = append(set, verbArgs("get_cookies", append([]types.String{s.GetCookies.Name}, s.GetCookies.URLs...)...))
+	}
+	if s.DeleteCookie != nil {
+		args := verbArgs("delete_cookie", s.DeleteCookie.Name)
+		set = append(set, append(args, CookieStepModel{Domain: s.DeleteCookie.Domain, Path: s.DeleteCookie.Path, URL: s.DeleteCookie.URL}.args()...))
+	}
+	if s.ClearCookies != nil {
+		set = append(set, verbArgs("clear_cookies"))
+	}
+	if s.SetValue != nil {
+		set = append(set, verbArgs("set_value", s.SetValue.Selector, s.SetValue.Value))
+	}
+	if s.PressEnter != nil {
+		set = append(set, verbArgs("press_enter", s.PressEnter.Selector))
+	}
+	if s.SendKeys != nil {
+		set = append(set, verbArgs("send_keys", s.SendKeys.Selector, s.SendKeys.Value))
+	}
+
+	if s.Evaluate != nil {
+		args := verbArgs("evaluate", s.Evaluate.Expression, types.StringValue(s.Evaluate.Name.ValueString()))
+		if s.Evaluate.AwaitPromise.ValueBool() {
+			args = append(args, types.StringValue("await"))
+		}
+		if s.Evaluate.JSON.ValueBool() {
+			args = append(args, types.StringValue("json"))
+		}
+		set = append(set, args)
+	}
+
+	if s.TextAll != nil {
+		set = append(set, verbArgs("text_all", s.TextAll.Selector, s.TextAll.Name))
+	}
+	if s.ValueAll != nil {
+		set = append(set, verbArgs("value_all", s.ValueAll.Selector, s.ValueAll.Name))
+	}
+	if s.AttributeAll != nil {
+		set = append(set, verbArgs("attribute_all", s.AttributeAll.Selector, s.AttributeAll.Attribute, s.AttributeAll.Name))
+	}
+
+	if s.Table != nil {
+		args := verbArgs("table", s.Table.Selector, s.Table.Name)
+		if !s.Table.HeaderRow.IsNull() {
+			args = append(args, types.StringValue(fmt.Sprintf("header_row=%d", s.Table.HeaderRow.ValueInt64())))
+		}
+		set = append(set, args)
+	}
+
+	if s.Attribute != nil {
+		set = append(set, verbArgs("attribute", s.Attribute.Selector, s.Attribute.Attribute, s.Attribute.Name))
+	}
+	if s.Attributes != nil {
+		set = append(set, verbArgs("attributes", s.Attributes.Selector, s.Attributes.Name))
+	}
+	if s.SetAttribute != nil {
+		set = append(set, verbArgs("set_attribute", s.SetAttribute.Selector, s.SetAttribute.Attribute, s.SetAttribute.Value))
+	}
+	if s.RemoveAttribute != nil {
+		set = append(set, verbArgs("remove_attribute", s.RemoveAttribute.Selector, s.RemoveAttribute.Attribute))
+	}
+	if s.Screenshot != nil {
+		set = append(set, s.Screenshot.args())
+	}
+	if s.StorageGet != nil {
+		set = append(set, verbArgs("storage_get", s.StorageGet.Storage, s.StorageGet.Key, s.StorageGet.Name))
+	}
+	if s.StorageSet != nil {
+		set = append(set, verbArgs("storage_set", s.StorageSet.Storage, s.StorageSet.Key, s.StorageSet.Value))
+	}
+	if s.StorageRemove != nil {
+		set = append(set, verbArgs("storage_remove", s.StorageRemove.Storage, s.StorageRemove.Key))
+	}
+	if s.StorageClear != nil {
+		set = append(set, verbArgs("storage_clear", s.StorageClear.Storage))
+	}
+	if s.IfPresent != nil {
+		set = append(set, verbArgs(ifPresentVerb, s.IfPresent.Selector))
+	}
+	if s.IfVisible != nil {
+		set = append(set, verbArgs(ifVisibleVerb, s.IfVisible.Selector))
+	}
+	if s.ForEachNode != nil {
+		set = append(set, verbArgs(forEachNodeVerb, s.ForEachNode.Selector))
+	}
+	if s.End != nil {
+		set = append(set, verbArgs(endVerb))
+	}
+
+	if len(set) != 1 {
+		return nil, fmt.Errorf("step must define exactly one action, got %d", len(set))
+	}
+	args := set[0]
+	if s.Timeout.ValueString() != "" {
+		args = append(args, types.StringValue("timeout="+s.Timeout.ValueString()))
+	}
+	args = append(args, s.Retry.args()...)
+	if s.Sensitive.ValueBool() {
+		args = append(args, types.StringValue("sensitive"))
+	}
+	return args, nil
+}
+
+func selectorAttribute() schema.StringAttribute {
+	return schema.StringAttribute{
+		Required:            true,
+		MarkdownDescription: "Selector of the element.",
+		Validators:          []validator.String{stringvalidator.LengthAtLeast(1)},
+	}
+}
+
+func captureNameAttribute() schema.StringAttribute {
+	return schema.StringAttribute{
+		Required:            true,
+		MarkdownDescription: `Key in "values" attribute under which the caught value is placed.`,
+		Validators:          []validator.String{stringvalidator.LengthAtLeast(1)},
+	}
+}
+
+func cookieNameAttribute() schema.StringAttribute {
+	return schema.StringAttribute{
+		Required:            true,
+		MarkdownDescription: "Name of the cookie.",
+		Validators:          []validator.String{stringvalidator.LengthAtLeast(1)},
+	}
+}
+
+func selectorStepAttribute(description string) schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		Optional:            true,
+		MarkdownDescription: description,
+		Attributes: map[string]schema.Attribute{
+			"selector": selectorAttribute(),
+		},
+	}
+}
+
+func captureStepAttribute(description string) schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		Optional:            true,
+		MarkdownDescription: description,
+		Attributes: map[string]schema.Attribute{
+			"selector": selectorAttribute(),
+			"name":     captureNameAttribute(),
+		},
+	}
+}
+
+func attributeNameAttribute() schema.StringAttribute {
+	return schema.StringAttribute{
+		Required:            true,
+		MarkdownDescription: "Name of the attribute.",
+		Validators:          []validator.String{stringvalidator.LengthAtLeast(1)},
+	}
+}
+
+func webStorageAttribute() schema.StringAttribute {
+	return schema.StringAttribute{
+		Required:            true,
+		MarkdownDescription: `Storage of the current page: "local" for localStorage or "session" for sessionStorage.`,
+		Validators:          []validator.String{stringvalidator.OneOf("local", "session")},
+	}
+}
+
+func storageKeyAttribute() schema.StringAttribute {
+	return schema.StringAttribute{
+		Required:            true,
+		MarkdownDescription: "Key of the item.",
+	}
+}
+
+func attributeStepAttribute(description string) schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		Optional:            true,
+		MarkdownDescription: description,
+		Attributes: map[string]schema.Attribute{
+			"selector":  selectorAttribute(),
+			"attribute": attributeNameAttribute(),
+			"name":      captureNameAttribute(),
+		},
+	}
+}
+
+func inputStepAttribute(description string) schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		Optional:            true,
+		MarkdownDescription: description,
+		Attributes: map[string]schema.Attribute{
+			"selector": selectorAttribute(),
+			"value": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Value to use.",
+			},
+		},
+	}
+}
+
+func stepsAttribute() schema.ListNestedAttribute {
+	return schema.ListNestedAttribute{
+		Optional:            true,
+		MarkdownDescription: "List of typed actions. Alternative to **actions** where every action is an object with exactly one attribute set, e.g. `{ click = { selector = \"#example-After\", wait_visible = true } }`.",
+		NestedObject: schema.NestedAttributeObject{
+			Validators: []validator.Object{stepValidator{}},
+			Attributes: map[string]schema.Attribute{
+				"timeout": schema.StringAttribute{
+					Optional:            true,
+					MarkdownDescription: `Timeout of the step's action, like "30s". Overrides **default_action_timeout** of the provider.`,
+					Validators:          []validator.String{durationValidator{positive: true}},
+				},
+				"retry": retryAttribute("Retry policy of the step's action. Overrides attributes of **retry** policy of the recipe."),
+				"sensitive": schema.BoolAttribute{
+					Optional:            true,
+					MarkdownDescription: "Places the value caught by the step's action into **sensitive_values** instead of **values** and hides arguments of the action in logs and errors.",
+				},
+				"navigate": schema.SingleNestedAttribute{
+					Optional:            true,
+					MarkdownDescription: "Navigates the current frame to specific URL.",
+					Attributes: map[string]schema.Attribute{
+						"url": schema.StringAttribute{
+							Required:            true,
+							MarkdownDescription: "URL to navigate to.",
+							Validators:          []validator.String{stringvalidator.LengthAtLeast(1)},
+						},
+					},
+				},
+				"wait_visible": selectorStepAttribute("Waits until selector matched element is visible."),
+				"click": schema.SingleNestedAttribute{
+					Optional:            true,
+					MarkdownDescription: "Sends a mouse click event to the first element node matching the selector.",
+					Attributes: map[string]schema.Attribute{
+						"selector": selectorAttribute(),
+						"wait_visible": schema.BoolAttribute{
+							Optional:            true,
+							MarkdownDescription: "Waits for all queried elements are visible before the click.",
+						},
+					},
+				},
+				"value": captureStepAttribute(`Gets value of form, input, textarea, select, or any other element with a ".value" field.`),
+				"focus": selectorStepAttribute("Focuses the first element node matching the selector."),
+				"sleep": schema.SingleNestedAttribute{
+					Optional:            true,
+					MarkdownDescription: "Waits specific duration.",
+					Attributes: map[string]schema.Attribute{
+						"duration": schema.StringAttribute{
+							Required:            true,
+							MarkdownDescription: `Duration like "1.5h" or "1m". Valid time units are "ns", "us", "ms", "s", "m", "h".`,
+							Validators:          []validator.String{durationValidator{}},
+						},
+					},
+				},
+				"text": captureStepAttribute("Retrieves the visible text of the first element node matching the selector."),
+				"cookie": schema.SingleNestedAttribute{
+					Optional:            true,
+					MarkdownDescription: fmt.Sprintf("Sets the cookie. The cookie expires in %s unless **session**, **expires** or **max_age** is set.", defaultCookieTTL),
+					Attributes: map[string]schema.Attribute{
+						"name": cookieNameAttribute(),
+						"value": schema.StringAttribute{
+							Required:            true,
+							MarkdownDescription: "Value of the cookie.",
+						},
+						"domain": schema.StringAttribute{
+							Optional:            true,
+							MarkdownDescription: "Domain of the cookie.",
+						},
+						"path": schema.StringAttribute{
+							Optional:            true,
+							MarkdownDescription: "Path of the cookie.",
+						},
+						"url": schema.StringAttribute{
+							Optional:            true,
+							MarkdownDescription: "URL to associate the cookie with. It sets default domain, path and scheme of the cookie.",
+						},
+						"secure": schema.BoolAttribute{
+							Optional:            true,
+							MarkdownDescription: "Sends the cookie over HTTPS only.",
+						},
+						"http_only": schema.BoolAttribute{
+							Optional:            true,
+							MarkdownDescription: "Hides the cookie from JavaScript.",
+						},
+						"same_site": schema.StringAttribute{
+							Optional:            true,
+							MarkdownDescription: `SameSite attribute of the cookie: "Strict", "Lax" or "None".`,
+							Validators:          []validator.String{stringvalidator.OneOf("Strict", "Lax", "None")},
+						},
+						"session": schema.BoolAttribute{
+							Optional:            true,
+							MarkdownDescription: "Makes the cookie a session cookie without expiration date.",
+						},
+						"expires": schema.StringAttribute{
+							Optional:            true,
+							MarkdownDescription: `Expiration date of the cookie in RFC 3339 format, like "2030-01-02T15:04:05Z".`,
+							Validators: []validator.String{
+								rfc3339Validator{},
+								stringvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("max_age")),
+							},
+						},
+						"max_age": schema.StringAttribute{
+							Optional:            true,
+							MarkdownDescription: `Lifetime of the cookie, like "1h" or "720h".`,
+							Validators:          []validator.String{durationValidator{}},
+						},
+					},
+				},
+				"get_cookies": schema.SingleNestedAttribute{
+					Optional:            true,
+					MarkdownDescription: "Gets cookies as a list of objects.",
+					Attributes: map[string]schema.Attribute{
+						"name": captureNameAttribute(),
+						"urls": schema.ListAttribute{
+							ElementType:         types.StringType,
+							Optional:            true,
+							MarkdownDescription: "URLs to get cookies for. If not set, cookies of the current page are returned.",
+						},
+					},
+				},
+				"delete_cookie": schema.SingleNestedAttribute{
+					Optional:            true,
+					MarkdownDescription: "Deletes cookies matching the name and optional domain, path and URL.",
+					Attributes: map[string]schema.Attribute{
+						"name": cookieNameAttribute(),
+						"domain": schema.StringAttribute{
+							Optional:            true,
+							MarkdownDescription: "Deletes only cookies with exactly this domain.",
+						},
+						"path": schema.StringAttribute{
+							Optional:            true,
+							MarkdownDescription: "Deletes only cookies with exactly this path.",
+						},
+						"url": schema.StringAttribute{
+							Optional:            true,
+							MarkdownDescription: "Deletes all cookies matching the URL.",
+						},
+					},
+				},
+				"clear_cookies": schema.SingleNestedAttribute{
+					Optional:            true,
+					MarkdownDescription: "Clears all cookies of the recipe, e.g. `{ clear_cookies = {} }`.",
+					Attributes:          map[string]schema.Attribute{},
+				},
+				"set_value":     inputStepAttribute(`Sets value of form, input, textarea or any other element with a ".value" field.`),
+				"press_enter":   selectorStepAttribute("Sends Enter key to the first element node matching the selector."),
+				"send_keys":     inputStepAttribute("Synthesizes the key up, char, and down events."),
+				"text_all":      captureStepAttribute("Retrieves the text of every element node matching the selector as a list."),
+				"value_all":     captureStepAttribute(`Gets ".value" field of every element node matching the selector as a list.`),
+				"attribute_all": attributeStepAttribute("Gets the attribute of every element node matching the selector as a list. Nodes without the attribute give empty strings."),
+				"attribute":     attributeStepAttribute("Gets the attribute of the first element node matching the selector. Missing attribute gives empty string."),
+				"attributes":    captureStepAttribute("Gets all attributes of the first element node matching the selector as a map."),
+				"set_attribute": schema.SingleNestedAttribute{
+					Optional:            true,
+					MarkdownDescription: "Sets the attribute of the first element node matching the selector.",
+					Attributes: map[string]schema.Attribute{
+						"selector":  selectorAttribute(),
+						"attribute": attributeNameAttribute(),
+						"value": schema.StringAttribute{
+							Required:            true,
+							MarkdownDescription: "Value of the attribute.",
+						},
+					},
+				},
+				"remove_attribute": schema.SingleNestedAttribute{
+					Optional:            true,
+					MarkdownDescription: "Removes the attribute from the first element node matching the selector.",
+					Attributes: map[string]schema.Attribute{
+						"selector":  selectorAttribute(),
+						"attribute": attributeNameAttribute(),
+					},
+				},
+				"screenshot": schema.SingleNestedAttribute{
+					Optional:            true,
+					MarkdownDescription: "Makes the screenshot and saves it into the file, creating its directory.",
+					Attributes: map[string]schema.Attribute{
+						"filename": schema.StringAttribute{
+							Required:            true,
+							MarkdownDescription: "Path of the screenshot file.",
+							Validators:          []validator.String{stringvalidator.LengthAtLeast(1)},
+						},
+						"selector": schema.StringAttribute{
+							Optional:            true,
+							MarkdownDescription: "Selector of the element to capture. The visible part of the page is captured if not set.",
+							Validators:          []validator.String{stringvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("full_page"))},
+						},
+						"full_page": schema.BoolAttribute{
+							Optional:            true,
+							MarkdownDescription: "Captures the whole page.",
+						},
+						"format": schema.StringAttribute{
+							Optional:            true,
+							MarkdownDescription: `Image format: "png", "jpeg" or "webp". Defaults to "png".`,
+							Validators:          []validator.String{stringvalidator.OneOf("png", "jpeg", "webp")},
+						},
+						"quality": schema.Int64Attribute{
+							Optional:            true,
+							MarkdownDescription: `Compression quality from 1 to 100. Requires **format** to be "jpeg" or "webp".`,
+							Validators:          []validator.Int64{int64validator.Between(1, 100)},
+						},
+						"scale": schema.Float64Attribute{
+							Optional:            true,
+							MarkdownDescription: "Scale of the screenshot, e.g. 2 doubles its width and height. Defaults to 1.",
+							Validators:          []validator.Float64{float64validator.AtLeast(0.01)},
+						},
+					},
+				},
+				"storage_get": schema.SingleNestedAttribute{
+					Optional:            true,
+					MarkdownDescription: "Gets the item of the current page's storage, missing item gives empty string.",
+					Attributes: map[string]schema.Attribute{
+						"storage": webStorageAttribute(),
+						"key":     storageKeyAttribute(),
+						"name":    captureNameAttribute(),
+					},
+				},
+				"storage_set": schema.SingleNestedAttribute{
+					Optional:            true,
+					MarkdownDescription: "Sets the item of the current page's storage.",
+					Attributes: map[string]schema.Attribute{
+						"storage": webStorageAttribute(),
+						"key":     storageKeyAttribute(),
+						"value": schema.StringAttribute{
+							Required:            true,
+							MarkdownDescription: "Value of the item.",
+						},
+					},
+				},
+				"storage_remove": schema.SingleNestedAttribute{
+					Optional:            true,
+					MarkdownDescription: "Removes the item from the current page's storage.",
+					Attributes: map[string]schema.Attribute{
+						"storage": webStorageAttribute(),
+						"key":     storageKeyAttribute(),
+					},
+				},
+				"storage_clear": schema.SingleNestedAttribute{
+					Optional:            true,
+					MarkdownDescription: "Removes all items from the current page's storage.",
+					Attributes: map[string]schema.Attribute{
+						"storage": webStorageAttribute(),
+					},
+				},
+				"if_present":    selectorStepAttribute("Runs the following steps up to `{ end = {} }` only if any node matches the selector. Doesn't wait for the node."),
+				"if_visible":    selectorStepAttribute("Runs the following steps up to `{ end = {} }` only if any node matching the selector is visible. Doesn't wait for the node."),
+				"for_each_node": selectorStepAttribute("Runs the following steps up to `{ end = {} }` for every node matching the selector. Arguments of the steps can refer to the node with \"${node}\" and \"${node.index}\" placeholders."),
+				"end": schema.SingleNestedAttribute{
+					Optional:            true,
+					MarkdownDescription: "Closes the innermost **if_present**, **if_visible** or **for_each_node** step, e.g. `{ end = {} }`.",
+					Attributes:          map[string]schema.Attribute{},
+				},
+				"table": schema.SingleNestedAttribute{
+					Optional:            true,
+					MarkdownDescription: "Reads the first table matching the selector as a list of maps from column name to cell text.",
+					Attributes: map[string]schema.Attribute{
+						"selector": selectorAttribute(),
+						"name":     captureNameAttribute(),
+						"header_row": schema.Int64Attribute{
+							Optional:            true,
+							MarkdownDescription: "Index of the row with column names, rows above it are skipped. Defaults to 0.",
+							Validators:          []validator.Int64{int64validator.AtLeast(0)},
+						},
+						"columns": schema.MapAttribute{
+							ElementType:         types.StringType,
+							Optional:            true,
+							MarkdownDescription: "Renames columns. Keys are texts of header cells, values are new names. Placeholders aren't replaced in columns.",
+						},
+					},
+				},
+				"evaluate": schema.SingleNestedAttribute{
+					Optional:            true,
+					MarkdownDescription: "Evaluates JavaScript expression.",
+					Attributes: map[string]schema.Attribute{
+						"expression": schema.StringAttribute{
+							Required:            true,
+							MarkdownDescription: "JavaScript expression to evaluate.",
+							Validators:          []validator.String{stringvalidator.LengthAtLeast(1)},
+						},
+						"name": schema.StringAttribute{
+							Optional:            true,
+							MarkdownDescription: `Key in "values" attribute under which the result is placed. If not set, the result is ignored.`,
+						},
+						"await_promise": schema.BoolAttribute{
+							Optional:            true,
+							MarkdownDescription: "Waits for the promise returned by the expression to be resolved.",
+						},
+						"json": schema.BoolAttribute{
+							Optional:            true,
+							MarkdownDescription: "Places the result JSON-encoded even if it is a string.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+var _ validator.Object = stepValidator{}
+
+// stepValidator ensures that exactly one action is defined in the step.
+type stepValidator struct{}
+
+func (v stepValidator) Description(ctx context.Context) string {
+	return "step must define exactly one action"
+}
+
+func (v stepValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v stepValidator) ValidateObject(ctx context.Context, req validator.ObjectRequest, resp *validator.ObjectResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	var set []string
+	for name, value := range req.ConfigValue.Attributes() {
+		if name == "timeout" || name == "retry" || name == "sensitive" {
+			continue
+		}
+		if !value.IsNull() {
+			set = append(set, name)
+		}
+	}
+
+	sort.Strings(set)
+	if len(set) != 1 {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Step",
+			fmt.Sprintf("Step must define exactly one action, got %d: %v", len(set), set),
+		)
+	}
+}
@@ -0,0 +1,122 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStepModel_actionArgs(t *testing.T) {
+	args, err := StepModel{
+		Click: &ClickStepModel{
+			Selector:    types.StringValue("#example-After"),
+			WaitVisible: types.BoolValue(true),
+		},
+	}.actionArgs()
+	assert.NoError(t, err)
+	assert.Equal(t, []types.String{
+		types.StringValue("click"),
+		types.StringValue("#example-After"),
+		types.StringValue("visible"),
+	}, args)
+
+	action, err := actionBuilder(args)
+	assert.NoError(t, err)
+	assert.NotNil(t, action)
+
+	args, err = StepModel{
+		Cookie: &CookieStepModel{
+			Name:   types.StringValue("key"),
+			Value:  types.StringValue("value"),
+			Domain: types.StringNull(),
+		},
+	}.actionArgs()
+	assert.NoError(t, err)
+	assert.Len(t, args, 3)
+
+	args, err = StepModel{
+		Cookie: &CookieStepModel{
+			Name:     types.StringValue("key"),
+			Value:    types.StringValue("value"),
+			Domain:   types.StringValue("example.com"),
+			SameSite: types.StringValue("Lax"),
+			Secure:   types.BoolValue(true),
+			Session:  types.BoolValue(true),
+		},
+	}.actionArgs()
+	assert.NoError(t, err)
+	assert.Equal(t, testArgs("cookie", "key", "value", "domain=example.com", "same_site=Lax", "secure", "session"), args)
+	_, err = actionBuilder(args)
+	assert.NoError(t, err)
+
+	args, err = StepModel{
+		DeleteCookie: &DeleteCookieStepModel{
+			Name: types.StringValue("key"),
+			Path: types.StringValue("/"),
+		},
+	}.actionArgs()
+	assert.NoError(t, err)
+	assert.Equal(t, testArgs("delete_cookie", "key", "path=/"), args)
+
+	args, err = StepModel{
+		WaitVisible: &SelectorStepModel{Selector: types.StringValue("body footer")},
+		Timeout:     types.StringValue("10s"),
+	}.actionArgs()
+	assert.NoError(t, err)
+	assert.Equal(t, testArgs("wait_visible", "body footer", "timeout=10s"), args)
+
+	args, err = StepModel{
+		Click: &ClickStepModel{Selector: types.StringValue("#submit")},
+		Retry: &RetryModel{Attempts: types.Int64Value(3), Backoff: types.StringNull()},
+	}.actionArgs()
+	assert.NoError(t, err)
+	assert.Equal(t, testArgs("click", "#submit", "retry=3"), args)
+
+	args, err = StepModel{
+		Text:      &CaptureStepModel{Selector: types.StringValue("#api-token"), Name: types.StringValue("token")},
+		Sensitive: types.BoolValue(true),
+	}.actionArgs()
+	assert.NoError(t, err)
+	assert.Equal(t, testArgs("text", "#api-token", "token", "sensitive"), args)
+
+	args, err = StepModel{ClearCookies: &EmptyStepModel{}}.actionArgs()
+	assert.NoError(t, err)
+	assert.Equal(t, testArgs("clear_cookies"), args)
+
+	_, err = StepModel{}.actionArgs()
+	assert.Error(t, err)
+
+	_, err = StepModel{
+		Navigate: &NavigateStepModel{URL: types.StringValue("about:blank")},
+		Sleep:    &SleepStepModel{Duration: types.StringValue("1s")},
+	}.actionArgs()
+	assert.Error(t, err)
+}
+
+func TestStepModel_tableColumns(t *testing.T) {
+	step := StepModel{
+		Table: &TableStepModel{
+			Selector:  types.StringValue("#users"),
+			Name:      types.StringValue("users"),
+			HeaderRow: types.Int64Value(1),
+			Columns: types.MapValueMust(types.StringType, map[string]attr.Value{
+				"Price = Tax": types.StringValue("price=tax"),
+				"Role":        types.StringUnknown(),
+			}),
+		},
+	}
+	args, err := step.actionArgs()
+	assert.NoError(t, err)
+	assert.Equal(t, testArgs("table", "#users", "users", "header_row=1"), args)
+	assert.Equal(t, map[string]string{"Price = Tax": "price=tax"}, step.tableColumns())
+
+	defs, err := actionDefinitions(nil, []StepModel{step})
+	if assert.NoError(t, err) && assert.Len(t, defs, 1) {
+		_, err = defs[0].build(defs[0].args)
+		assert.NoError(t, err)
+	}
+
+	assert.Nil(t, StepModel{ClearCookies: &EmptyStepModel{}}.tableColumns())
+}
@@ -0,0 +1,103 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/chromedp"
+)
+
+// tableCellsJS returns text of all cells of the table row by row.
+const tableCellsJS = `function() {
+	const rows = Array.from(this.rows || this.querySelectorAll("tr"));
+	return rows.map(row => Array.from(row.cells || row.querySelectorAll("th, td"), cell => cell.textContent.trim()));
+}`
+
+type tableOptions struct {
+	// headerRow is index of the row with column names, rows above it are skipped.
+	headerRow int
+	// columns renames columns, keys are texts of header cells.
+	columns map[string]string
+}
+
+// parse parses option of the table action: "header_row=<index>" or "column:<header text>=<new name>".
+func (o *tableOptions) parse(opt string) error {
+	if strings.HasPrefix(opt, "column:") {
+		column := strings.TrimPrefix(opt, "column:")
+		i := strings.LastIndex(column, "=")
+		if i < 0 {
+			return fmt.Errorf(`table column option must be like "column:<header text>=<new name>", got %q`, opt)
+		}
+		o.columns[column[:i]] = column[i+1:]
+		return nil
+	}
+
+	if strings.HasPrefix(opt, "header_row=") {
+		index, err := strconv.Atoi(strings.TrimPrefix(opt, "header_row="))
+		if err != nil || index < 0 {
+			return fmt.Errorf("table header_row option must be non-negative integer, got %q", opt)
+		}
+		o.headerRow = index
+		return nil
+	}
+
+	return fmt.Errorf(`unknown table option %q, expected "header_row=<index>" or "column:<header text>=<new name>"`, opt)
+}
+
+// rows converts cells of the table into list of maps from column name to cell text.
+// Header cells without text are named "column_<index>" and rows with less cells than header miss trailing columns.
+func (o tableOptions) rows(cells [][]string) ([]map[string]string, error) {
+	if o.headerRow >= len(cells) {
+		return nil, fmt.Errorf("table has %d rows, header row %d doesn't exist", len(cells), o.headerRow)
+	}
+
+	header := make([]string, 0, len(cells[o.headerRow]))
+	for i, name := range cells[o.headerRow] {
+		if name == "" {
+			name = fmt.Sprintf("column_%d", i)
+		}
+		if rename, ok := o.columns[name]; ok {
+			name = rename
+		}
+		header = append(header, name)
+	}
+
+	rows := make([]map[string]string, 0, len(cells)-o.headerRow-1)
+	for _, rowCells := range cells[o.headerRow+1:] {
+		row := make(map[string]string, len(header))
+		for i, cell := range rowCells {
+			if i >= len(header) {
+				break
+			}
+			row[header[i]] = cell
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// table reads the first table matching the selector into res.
+func table(selector string, res *[]map[string]string, opts tableOptions) chromedp.Action {
+	return chromedp.QueryAfter(selector, func(ctx context.Context, _ runtime.ExecutionContextID, nodes ...*cdp.Node) error {
+		if len(nodes) < 1 {
+			return fmt.Errorf("selector %q did not return any nodes", selector)
+		}
+
+		var cells [][]string
+		err := callNodeFunction(ctx, nodes[0], tableCellsJS, &cells)
+		if err != nil {
+			return err
+		}
+
+		rows, err := opts.rows(cells)
+		if err != nil {
+			return err
+		}
+		*res = rows
+		return nil
+	})
+}
@@ -0,0 +1,35 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTableOptions(t *testing.T) {
+	opts := tableOptions{columns: map[string]string{}}
+	assert.NoError(t, opts.parse("header_row=1"))
+	assert.NoError(t, opts.parse("column:Full Name=name"))
+	assert.NoError(t, opts.parse("column:a=b=c"))
+	assert.Error(t, opts.parse("header_row=-1"))
+	assert.Error(t, opts.parse("column:name"))
+	assert.Error(t, opts.parse("headers"))
+
+	assert.Equal(t, 1, opts.headerRow)
+	assert.Equal(t, map[string]string{"Full Name": "name", "a=b": "c"}, opts.columns)
+
+	rows, err := opts.rows([][]string{
+		{"Users"},
+		{"Full Name", "Role", ""},
+		{"John Doe", "admin", "x"},
+		{"Jane Doe"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []map[string]string{
+		{"name": "John Doe", "Role": "admin", "column_2": "x"},
+		{"name": "Jane Doe"},
+	}, rows)
+
+	_, err = tableOptions{headerRow: 2}.rows([][]string{{"a"}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,104 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+var _ validator.String = durationValidator{}
+
+// durationValidator checks that the string can be parsed by time.ParseDuration.
+type durationValidator struct {
+	// positive rejects zero and negative durations, like of timeouts which can't be disabled with "0s".
+	positive bool
+}
+
+func (v durationValidator) Description(ctx context.Context) string {
+	if v.positive {
+		return `value must be a positive duration like "1.5h" or "1m"`
+	}
+	return `value must be a duration like "1.5h" or "1m"`
+}
+
+func (v durationValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v durationValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	d, err := time.ParseDuration(req.ConfigValue.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Duration",
+			fmt.Sprintf("Can't parse duration %q: %v", req.ConfigValue.ValueString(), err),
+		)
+		return
+	}
+	if v.positive && d <= 0 {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Duration",
+			fmt.Sprintf("Duration must be positive, got %q.", req.ConfigValue.ValueString()),
+		)
+	}
+}
+
+var _ validator.String = rfc3339Validator{}
+
+// rfc3339Validator checks that the string is a timestamp in RFC 3339 format.
+type rfc3339Validator struct{}
+
+func (v rfc3339Validator) Description(ctx context.Context) string {
+	return `value must be a timestamp in RFC 3339 format like "2030-01-02T15:04:05Z"`
+}
+
+func (v rfc3339Validator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v rfc3339Validator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	_, err := time.Parse(time.RFC3339, req.ConfigValue.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Timestamp",
+			fmt.Sprintf("Can't parse timestamp %q: %v", req.ConfigValue.ValueString(), err),
+		)
+	}
+}
+
+var _ validator.String = sessionValidator{}
+
+// sessionValidator checks that the string is the session exported by chromedp_session data source.
+type sessionValidator struct{}
+
+func (v sessionValidator) Description(ctx context.Context) string {
+	return "value must be the session exported by chromedp_session data source"
+}
+
+func (v sessionValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v sessionValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	_, err := parseSession(req.ConfigValue.ValueString())
+	if err != nil {
+		// The session is sensitive, so it isn't included in the error.
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid Session", err.Error())
+	}
+}
@@ -0,0 +1,29 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDurationValidator(t *testing.T) {
+	validate := func(v durationValidator, value types.String) bool {
+		var resp validator.StringResponse
+		v.ValidateString(context.Background(), validator.StringRequest{Path: path.Root("timeout"), ConfigValue: value}, &resp)
+		return !resp.Diagnostics.HasError()
+	}
+
+	for _, value := range []string{"30s", "1.5h"} {
+		assert.True(t, validate(durationValidator{positive: true}, types.StringValue(value)), value)
+	}
+	for _, value := range []string{"0s", "-1m", "soon"} {
+		assert.False(t, validate(durationValidator{positive: true}, types.StringValue(value)), value)
+	}
+	assert.True(t, validate(durationValidator{}, types.StringValue("-1m")))
+	assert.False(t, validate(durationValidator{}, types.StringValue("soon")))
+	assert.True(t, validate(durationValidator{positive: true}, types.StringUnknown()))
+}
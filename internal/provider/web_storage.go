@@ -0,0 +1,53 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/chromedp/chromedp"
+)
+
+// webStorage is the name of the page's Web Storage object: localStorage or sessionStorage.
+type webStorage string
+
+const (
+	localWebStorage   webStorage = "localStorage"
+	sessionWebStorage webStorage = "sessionStorage"
+)
+
+// parseWebStorage parses storage argument of storage actions: "local" or "session".
+func parseWebStorage(name string) (webStorage, error) {
+	switch name {
+	case "local":
+		return localWebStorage, nil
+	case "session":
+		return sessionWebStorage, nil
+	}
+	return "", fmt.Errorf(`storage must be "local" or "session", got %q`, name)
+}
+
+// jsString encodes the string as JavaScript string literal.
+func jsString(s string) string {
+	encoded, _ := json.Marshal(s)
+	return string(encoded)
+}
+
+// storageGet gets the item of the current page's storage, missing item gives empty string.
+func storageGet(storage webStorage, key string, res *string) chromedp.Action {
+	return chromedp.Evaluate(fmt.Sprintf(`%s.getItem(%s) ?? ""`, storage, jsString(key)), res)
+}
+
+// storageSet sets the item of the current page's storage.
+func storageSet(storage webStorage, key, value string) chromedp.Action {
+	return chromedp.Evaluate(fmt.Sprintf(`%s.setItem(%s, %s)`, storage, jsString(key), jsString(value)), nil)
+}
+
+// storageRemove removes the item from the current page's storage.
+func storageRemove(storage webStorage, key string) chromedp.Action {
+	return chromedp.Evaluate(fmt.Sprintf(`%s.removeItem(%s)`, storage, jsString(key)), nil)
+}
+
+// storageClear removes all items from the current page's storage.
+func storageClear(storage webStorage) chromedp.Action {
+	return chromedp.Evaluate(fmt.Sprintf(`%s.clear()`, storage), nil)
+}
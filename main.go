@@ -43,6 +43,7 @@ func main() {
 	}
 
 	err := providerserver.Serve(context.Background(), provider.New(version), opts)
+	provider.CloseBrowsers()
 
 	if err != nil {
 		log.Fatal(err.Error())